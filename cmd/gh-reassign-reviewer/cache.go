@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/spf13/cobra"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the local API response cache",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Delete every cached API response",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := httpCacheDir()
+			if err := github.ClearCache(dir); err != nil {
+				return err
+			}
+			fmt.Printf("Cleared %s\n", dir)
+			return nil
+		},
+		SilenceUsage: true,
+	})
+	return cmd
+}
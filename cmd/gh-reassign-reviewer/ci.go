@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ci"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// runCI re-requests reviews without prompting, using the GitHub Actions environment
+func runCI(ctx context.Context, args []string, opts options, strategy service.Strategy, env ci.Environment) error {
+	if opts.tui {
+		return fmt.Errorf("--tui cannot be used in CI")
+	}
+
+	repo, prNumber, err := ciPullRequest(args, env)
+	if err != nil {
+		return err
+	}
+	repoName := repo.Owner + "/" + repo.Name
+
+	// As outside CI, the host of a PR URL is where the PR lives
+	var client *github.Client
+	if isPullRequestURL(args) {
+		host, _, _ := github.ParsePullRequestURL(args[0])
+		client, err = newGitHubClientFor(host, env.Token)
+	} else {
+		client, err = newGitHubClient(env.Host, env.Token)
+	}
+	if err != nil {
+		return err
+	}
+
+	self, err := ciSelf(ctx, client, env, prNumber)
+	if err != nil {
+		return err
+	}
+
+	// Nobody can answer a prompt in CI, so reviewers default to the stale ones
+	if strategy == service.StrategyPrompt {
+		strategy = service.StrategyStale
+	}
+
+	serviceOpts, err := serviceOptions(
+		service.WithSelf(self),
+		service.WithAutoConfirm(true),
+		service.WithReviewers(opts.reviewers),
+		service.WithStrategy(strategy),
+		service.WithIncludeBusy(opts.includeBusy),
+		service.WithHandoffComment(opts.handoffComment),
+		service.WithIncludeNew(opts.includeNew),
+		service.WithAuditMode(audit.ModeAuto),
+	)
+	if err != nil {
+		return err
+	}
+	reassignService := service.NewReassignService(client, repo, &ui.NonInteractivePrompter{}, serviceOpts...)
+
+	plan, err := reassignService.ProcessReassignment(ctx, []string{os.Args[0], strconv.Itoa(prNumber)})
+	if err != nil && !errors.Is(err, service.ErrNothingToRequest) {
+		return err
+	}
+
+	if err := env.WriteResult(plan, repoName, prNumber); err != nil {
+		return err
+	}
+
+	if plan == nil {
+		fmt.Println("No reviewers need to be re-requested")
+	} else {
+		printWarnings(plan.Warnings)
+		fmt.Printf("Successfully reassigned reviewers: %v\n", plan.Reviewers)
+	}
+	return nil
+}
+
+// ciPullRequest returns the PR given as a number or URL argument, like outside CI, or else the PR of the event.
+// A PR number refers to GITHUB_REPOSITORY.
+func ciPullRequest(args []string, env ci.Environment) (*github.Repository, int, error) {
+	if isPullRequestURL(args) {
+		_, ref, err := github.ParsePullRequestURL(args[0])
+		if err != nil {
+			return nil, 0, err
+		}
+		return &github.Repository{Owner: ref.Owner, Name: ref.Repo}, ref.Number, nil
+	}
+
+	repo, err := env.RepositoryInfo()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get repository from GITHUB_REPOSITORY: %w", err)
+	}
+	if len(args) == 0 {
+		prNumber, err := env.PullRequestNumber()
+		return repo, prNumber, err
+	}
+	prNumber, err := strconv.Atoi(args[0])
+	if err != nil || prNumber <= 0 {
+		return nil, 0, fmt.Errorf("invalid PR number %q: pass a PR number or URL", args[0])
+	}
+	return repo, prNumber, nil
+}
+
+// ciSelf works out who is excluded from the reviewers without GET /user, which GITHUB_TOKEN may not call:
+// the PR author when the event is about this PR, otherwise the user who triggered the event.
+// Without an event the API is asked, and nobody is excluded when the token may not read its own user.
+func ciSelf(ctx context.Context, client *github.Client, env ci.Environment, prNumber int) (string, error) {
+	if event, err := env.Event(); err == nil {
+		if event.PullRequestNumber() == prNumber && event.PullRequest.User.Login != "" {
+			return event.PullRequest.User.Login, nil
+		}
+		if event.Sender.Login != "" {
+			return event.Sender.Login, nil
+		}
+	}
+
+	self, err := client.GetCurrentUserLogin(ctx)
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusForbidden || httpErr.StatusCode == http.StatusUnauthorized) {
+		return "", nil
+	}
+	return self, err
+}
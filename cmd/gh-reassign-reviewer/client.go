@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/config"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/resume"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/xdg"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// globalOptions holds the flags shared by every subcommand
+type globalOptions struct {
+	verbose     bool
+	timeout     time.Duration
+	concurrency int
+	noCache     bool
+	hostname    string
+	cooldown    time.Duration
+	force       bool
+
+	// cooldownFlag tells whether --cooldown was given, which overrides the cooldown of config.yaml
+	cooldownFlag *pflag.Flag
+
+	// config is read from the config directory before any subcommand runs
+	config *config.Config
+}
+
+var global globalOptions
+
+// commandContext returns the context of cmd. It has no deadline: --timeout bounds each API request instead,
+// since promptui prompts cannot be cancelled and waiting for the user is not what the timeout is for.
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return context.WithCancel(cmd.Context())
+}
+
+// resolveHost picks the API host: --hostname, then GH_HOST, then the host the repository lives on.
+// A PR URL names its host, so its host is used as it is.
+func resolveHost(fallback string) string {
+	if global.hostname != "" {
+		return global.hostname
+	}
+	if host := os.Getenv("GH_HOST"); host != "" {
+		return host
+	}
+	return fallback
+}
+
+// newGitHubClient creates a client honoring the global flags; an empty token is resolved from the gh environment
+func newGitHubClient(host, token string) (*github.Client, error) {
+	return newGitHubClientFor(resolveHost(host), token)
+}
+
+// newGitHubClientFor creates a client for exactly host, ignoring --hostname and GH_HOST
+func newGitHubClientFor(host, token string) (*github.Client, error) {
+	opts := github.ClientOptions{AuthToken: token, Concurrency: global.concurrency, Host: host, Timeout: global.timeout}
+	if !global.noCache {
+		opts.CacheDir = httpCacheDir()
+	}
+	if global.verbose {
+		opts.Log = os.Stderr
+	}
+
+	client, err := github.NewClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	return client, nil
+}
+
+// httpCacheDir is where REST responses are cached between runs
+func httpCacheDir() string {
+	return filepath.Join(xdg.CacheDir(), "http")
+}
+
+// auditLog is where every review request is recorded
+func auditLog() *audit.Log {
+	return audit.NewLog(filepath.Join(xdg.StateDir(), "audit.jsonl"))
+}
+
+// resumeStore is where a run stopped by the rate limit is saved for resume
+func resumeStore() *resume.Store {
+	return resume.NewStore(filepath.Join(xdg.StateDir(), "resume.json"))
+}
+
+// loadConfig reads config.yaml from the config directory on first use, so that commands which
+// never need it, such as history and undo, keep working when it is broken
+func loadConfig() (*config.Config, error) {
+	if global.config == nil {
+		cfg, err := config.Load(filepath.Join(xdg.ConfigDir(), config.FileName))
+		if err != nil {
+			return nil, err
+		}
+		global.config = cfg
+	}
+	return global.config, nil
+}
+
+// serviceOptions returns the service settings shared by every subcommand, followed by opts
+func serviceOptions(opts ...service.Option) ([]service.Option, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	cooldown := cfg.Cooldown
+	if global.cooldownFlag != nil && global.cooldownFlag.Changed {
+		cooldown = global.cooldown
+	}
+	shared := []service.Option{
+		service.WithAuditLog(auditLog()),
+		service.WithCooldown(cooldown),
+		service.WithForce(global.force),
+		service.WithAvailability(cfg.Availability),
+		service.WithBackups(cfg.Backups),
+		service.WithGroups(cfg.Groups),
+	}
+	return append(shared, opts...), nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/tui"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+type dashboardOptions struct {
+	org   string
+	query string
+	limit int
+	plain bool
+}
+
+func newDashboardCmd() *cobra.Command {
+	var opts dashboardOptions
+
+	cmd := &cobra.Command{
+		Use:   "dashboard",
+		Short: "Show your PRs waiting on reviewers and the PRs waiting on your review",
+		Long: "Searches every repository you can see, or --org, for open PRs you authored with pending or stale reviews " +
+			"and PRs where your review is requested, showing who is blocking and for how long. " +
+			"Press r to re-request the blockers of the highlighted PR.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			return runDashboard(ctx, opts)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&opts.org, "org", "", "Only include repositories of this organization or user")
+	cmd.Flags().StringVar(&opts.query, "query", "", "Extra search qualifiers, e.g. \"repo:owner/name label:urgent\"")
+	cmd.Flags().IntVar(&opts.limit, "limit", 100, "Maximum number of PRs read per search (0 reads every page)")
+	cmd.Flags().BoolVar(&opts.plain, "plain", false, "Print the dashboard instead of opening the TUI")
+	return cmd
+}
+
+func runDashboard(ctx context.Context, opts dashboardOptions) error {
+	client, err := newGitHubClient("", "")
+	if err != nil {
+		return err
+	}
+
+	query := opts.query
+	if opts.org != "" {
+		query = strings.TrimSpace("org:" + opts.org + " " + query)
+	}
+
+	serviceOpts, err := serviceOptions()
+	if err != nil {
+		return err
+	}
+	reassignService := service.NewReassignService(client, &github.Repository{}, &ui.NonInteractivePrompter{}, serviceOpts...)
+	items, self, err := reassignService.Dashboard(ctx, service.DashboardOptions{Query: query, Limit: opts.limit})
+	if err != nil {
+		return err
+	}
+
+	if opts.plain {
+		printDashboard(items)
+		return nil
+	}
+	return tui.RunDashboard(ctx, items, func(item models.DashboardItem) ([]string, error) {
+		return reassignService.ReRequest(ctx, item, self)
+	})
+}
+
+func printDashboard(items []models.DashboardItem) {
+	if len(items) == 0 {
+		fmt.Println("Nothing is waiting on reviews")
+		return
+	}
+
+	now := time.Now()
+	var role models.DashboardRole
+	for i, item := range items {
+		if item.Role != role {
+			role = item.Role
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(tui.DashboardHeading(role) + ":")
+		}
+		fmt.Println("  " + tui.DashboardLine(item, now, 200))
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ci"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newHandleEventCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "handle-event [payload file]",
+		Short: "Re-request stale reviewers from a pull_request synchronize event",
+		Long: "Reads a pull_request webhook payload from the given file, $GITHUB_EVENT_PATH or stdin, " +
+			"and re-requests reviewers whose last review predates the pushed commits.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			return runHandleEvent(ctx, args, cmd.InOrStdin())
+		},
+		SilenceUsage: true,
+	}
+}
+
+func runHandleEvent(ctx context.Context, args []string, stdin io.Reader) error {
+	env := ci.FromEnv(os.Getenv)
+
+	event, err := readEvent(args, env, stdin)
+	if err != nil {
+		return err
+	}
+
+	repoName := event.Repository.FullName
+	if repoName == "" {
+		repoName = env.Repository
+	}
+	repo, err := ci.ParseRepository(repoName)
+	if err != nil {
+		return fmt.Errorf("failed to get repository from event payload: %w", err)
+	}
+
+	client, err := newGitHubClient(env.Host, env.Token)
+	if err != nil {
+		return err
+	}
+
+	serviceOpts, err := serviceOptions()
+	if err != nil {
+		return err
+	}
+	reassignService := service.NewReassignService(client, repo, &ui.NonInteractivePrompter{}, serviceOpts...)
+	plan, err := reassignService.HandlePullRequestEvent(ctx, event)
+	switch {
+	case errors.Is(err, service.ErrIgnoredEvent):
+		fmt.Println(err)
+		return nil
+	case errors.Is(err, service.ErrNothingToRequest):
+		plan = nil
+	case err != nil:
+		return err
+	}
+
+	if env.Enabled {
+		if err := env.WriteResult(plan, repoName, event.PullRequestNumber()); err != nil {
+			return err
+		}
+	}
+
+	if plan == nil {
+		fmt.Println("No reviewers need to be re-requested")
+	} else {
+		fmt.Printf("Successfully reassigned reviewers: %v\n", plan.Reviewers)
+	}
+	return nil
+}
+
+// readEvent reads the payload from the file argument, $GITHUB_EVENT_PATH or stdin, in that order
+func readEvent(args []string, env ci.Environment, stdin io.Reader) (*models.PullRequestEvent, error) {
+	switch {
+	case len(args) > 0 && args[0] != "-":
+		return ci.ReadEventFile(args[0])
+	case len(args) == 0 && env.EventPath != "":
+		return env.Event()
+	default:
+		return ci.ReadEvent(stdin)
+	}
+}
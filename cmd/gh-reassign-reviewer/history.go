@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// historyOptions holds the flags of the history subcommand
+type historyOptions struct {
+	repo     string
+	pr       int
+	reviewer string
+	since    string
+	until    string
+}
+
+func newHistoryCmd() *cobra.Command {
+	var opts historyOptions
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the review requests recorded in the audit log",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHistory(cmd.OutOrStdout(), opts)
+		},
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&opts.repo, "repo", "", "Only show requests in this repository (owner/name)")
+	cmd.Flags().IntVar(&opts.pr, "pr", 0, "Only show requests on this PR number")
+	cmd.Flags().StringVar(&opts.reviewer, "reviewer", "", "Only show requests that included this reviewer")
+	cmd.Flags().StringVar(&opts.since, "since", "", "Only show requests on or after this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&opts.until, "until", "", "Only show requests on or before this date (YYYY-MM-DD or RFC 3339)")
+
+	return cmd
+}
+
+func runHistory(out io.Writer, opts historyOptions) error {
+	filter := audit.Filter{Repo: opts.repo, PR: opts.pr, Reviewer: opts.reviewer}
+
+	var err error
+	if opts.since != "" {
+		if filter.Since, _, err = config.ParseDate(opts.since); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if opts.until != "" {
+		until, dateOnly, err := config.ParseDate(opts.until)
+		if err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+		// A plain date includes the whole day
+		if dateOnly {
+			until = until.AddDate(0, 0, 1)
+		} else {
+			until = until.Add(time.Nanosecond)
+		}
+		filter.Until = until
+	}
+
+	log := auditLog()
+	entries, err := log.Read(filter)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(out, "No matching review requests in %s\n", log.Path())
+		return nil
+	}
+
+	for _, entry := range entries {
+		action := entry.Action
+		if action == "" {
+			action = audit.ActionRequest
+		}
+		line := fmt.Sprintf("%s  %s %s %s %s %s %s",
+			entry.Time.Local().Format("2006-01-02 15:04"),
+			runewidth.FillRight(entry.Actor, 16),
+			runewidth.FillRight(fmt.Sprintf("%s#%d", entry.Repo, entry.PR), 32),
+			runewidth.FillRight(string(action), 10),
+			runewidth.FillRight(string(entry.Mode), 11),
+			runewidth.FillRight(entry.Outcome, 7),
+			strings.Join(entry.Reviewers, ", "),
+		)
+		if entry.Error != "" {
+			line += " (" + entry.Error + ")"
+		}
+		fmt.Fprintln(out, line)
+	}
+	return nil
+}
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/cli/go-gh/v2/pkg/repository"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ci"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/tui"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -24,45 +33,211 @@ func (r *RepositoryAdapter) GetName() string {
 	return r.repo.Name
 }
 
-func runCommand() error {
-	// Get current repository
-	repo, err := repository.Current()
+// options holds the command line flags
+type options struct {
+	tui            bool
+	yes            bool
+	confirmDefault bool
+	confirmTimeout time.Duration
+	reviewers      []string
+	strategy       string
+	includeBusy    bool
+	handoffComment bool
+	includeNew     bool
+	org            string
+	allRepos       bool
+	sort           string
+	actions        bool
+}
+
+func runCommand(ctx context.Context, args []string, opts options) error {
+	strategy, err := service.ParseStrategy(opts.strategy)
+	if err != nil {
+		return err
+	}
+	sortMode, err := ui.ParseSortMode(opts.sort)
+	if err != nil {
+		return err
+	}
+
+	if env := ci.FromEnv(os.Getenv); env.Enabled {
+		return runCI(ctx, args, opts, strategy, env)
+	}
+
+	// Get the repository from a PR URL or the current directory
+	repo, err := targetRepository(args, opts.org != "" || opts.allRepos)
 	if err != nil {
-		return fmt.Errorf("failed to get current repository: %w", err)
+		return err
+	}
+
+	// The host of a PR URL is where the PR lives, so only the current repository's host can be overridden
+	host := repo.Host
+	if !isPullRequestURL(args) {
+		host = resolveHost(host)
 	}
 
 	// Initialize GitHub client
-	client, err := github.NewClient()
+	client, err := newGitHubClientFor(host, "")
 	if err != nil {
-		return fmt.Errorf("failed to create GitHub client: %w", err)
+		return err
 	}
 
 	// Create service with dependency injection
 	repoAdapter := &RepositoryAdapter{repo: &repo}
-	prompter := &ui.DefaultPrompter{}
-	reassignService := service.NewReassignService(client, repoAdapter, prompter)
+	actions := ui.NewActions(host)
+	preview := ui.CachePreviews(func(pr models.PullRequestInfo) (*models.PRPreview, error) {
+		return client.GetPullRequestPreview(ctx, pr.Owner, pr.Repo, pr.Number)
+	})
+	prompter := &ui.DefaultPrompter{ConfirmDefault: opts.confirmDefault, ConfirmTimeout: opts.confirmTimeout, Sort: sortMode, Preview: preview}
+	if opts.actions {
+		prompter.Actions = actions
+	}
+	serviceOpts, err := serviceOptions(
+		service.WithAutoConfirm(opts.yes),
+		service.WithReviewers(opts.reviewers),
+		service.WithStrategy(strategy),
+		service.WithIncludeBusy(opts.includeBusy),
+		service.WithHandoffComment(opts.handoffComment),
+		service.WithIncludeNew(opts.includeNew),
+		service.WithOrg(opts.org),
+		service.WithAllRepos(opts.allRepos),
+	)
+	if err != nil {
+		return err
+	}
+	reassignService := service.NewReassignService(client, repoAdapter, prompter, serviceOpts...)
 
 	// Process the reassignment
-	err = reassignService.ProcessReassignment(os.Args)
+	var plan *models.ReassignPlan
+	if opts.tui {
+		plan, err = runTUI(ctx, reassignService, sortMode, preview, actions)
+	} else {
+		plan, err = reassignService.ProcessReassignment(ctx, append([]string{os.Args[0]}, args...))
+	}
+	if errors.Is(err, service.ErrNothingToRequest) {
+		fmt.Println("No reviewers need to be re-requested")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
+	// Without a confirmation the warnings, e.g. skipped reviewers, have not been shown yet
+	if plan != nil && opts.yes {
+		printWarnings(plan.Warnings)
+	}
 	fmt.Println("Successfully reassigned reviewer")
 	return nil
 }
 
-func main() {
+// isPullRequestURL reports whether the PR is given as a URL rather than a number
+func isPullRequestURL(args []string) bool {
+	return len(args) > 0 && strings.Contains(args[0], "://")
+}
+
+// targetRepository returns the repository of a PR URL argument, or the current repository.
+// When PRs are picked across repositories, running outside a repository is fine.
+func targetRepository(args []string, crossRepo bool) (repository.Repository, error) {
+	if isPullRequestURL(args) {
+		host, ref, err := github.ParsePullRequestURL(args[0])
+		if err != nil {
+			return repository.Repository{}, err
+		}
+		return repository.Repository{Host: host, Owner: ref.Owner, Name: ref.Repo}, nil
+	}
+
+	repo, err := repository.Current()
+	if err != nil && crossRepo {
+		return repository.Repository{}, nil
+	}
+	if err != nil {
+		return repository.Repository{}, fmt.Errorf("failed to get current repository: %w", err)
+	}
+	return repo, nil
+}
+
+// runTUI lets the user pick the PR and reviewers in the full-screen TUI
+func runTUI(ctx context.Context, reassignService *service.ReassignService, sortMode ui.SortMode, preview ui.PreviewLoader, actions *ui.Actions) (*models.ReassignPlan, error) {
+	prs, self, err := reassignService.ListAssignedPRs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Already loaded by serviceOptions
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tui.Run(ctx, prs, cfg.Groups, sortMode, actions, preview, func(pr models.PullRequestInfo) ([]models.ReviewerStatus, error) {
+		return reassignService.ForRepository(pr.Owner, pr.Repo).GetReviewerStatuses(ctx, pr.Number, self)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reassignService.ForRepository(result.Owner, result.Repo).Reassign(ctx, result.PRNumber, result.Reviewers, self)
+}
+
+// printWarnings prints the warnings of a plan that was not confirmed interactively
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+}
+
+// newRootCmd builds the command line; resume builds it again to run a saved command
+func newRootCmd() *cobra.Command {
+	var opts options
+
 	cmd := &cobra.Command{
-		Use:   "reassign-reviewer",
+		Use:   "reassign-reviewer [PR number | PR URL]",
 		Short: "Reassign reviewers who have already been requested",
+		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCommand()
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			return runCommand(ctx, args, opts)
 		},
 		SilenceUsage: true,
 	}
+	cmd.AddCommand(newHandleEventCmd(), newServeCmd(), newCacheCmd(), newHistoryCmd(), newUndoCmd(), newSubstituteCmd(), newDashboardCmd(), newResumeCmd())
+	cmd.PersistentFlags().BoolVarP(&global.verbose, "verbose", "v", false, "Show API details such as the remaining rate limit quota")
+	cmd.PersistentFlags().DurationVar(&global.timeout, "timeout", 0, "Abort an API request, including its retries, that takes longer than this; with serve also each job (0 disables)")
+	cmd.PersistentFlags().IntVar(&global.concurrency, "concurrency", github.DefaultConcurrency, "Maximum number of GitHub API requests in flight")
+	cmd.PersistentFlags().BoolVar(&global.noCache, "no-cache", false, "Do not read or write the local API response cache")
+	cmd.PersistentFlags().StringVar(&global.hostname, "hostname", "", "GitHub host to use, e.g. a GitHub Enterprise Server (defaults to GH_HOST or the repository's host)")
+	cmd.PersistentFlags().DurationVar(&global.cooldown, "cooldown", 0, "Do not re-request a reviewer requested on the same PR within this duration who has not reviewed since (0 disables; defaults to cooldown in config.yaml)")
+	global.cooldownFlag = cmd.PersistentFlags().Lookup("cooldown")
+	cmd.PersistentFlags().BoolVar(&global.force, "force", false, "Re-request reviewers even within the cooldown")
+
+	cmd.Flags().BoolVar(&opts.tui, "tui", false, "Pick the PR and reviewers in a full-screen TUI")
+	cmd.Flags().StringVar(&opts.org, "org", "", "Pick from the PRs assigned to you in every repository of this organization")
+	cmd.Flags().BoolVar(&opts.allRepos, "all-repos", false, "Pick from the PRs assigned to you in every repository you can see")
+	cmd.MarkFlagsMutuallyExclusive("org", "all-repos")
+	cmd.Flags().StringVar(&opts.sort, "sort", "created", "Order of the PR picker: \"created\", \"updated\", \"number\" or \"review\" (press s in the TUI to change it)")
+	cmd.Flags().BoolVar(&opts.actions, "actions", false, "After each pick, offer to open the PR or reviewer in the browser, copy the PR URL or show its diff stats (the TUI always has them on keys)")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Re-request without asking for confirmation")
+	cmd.Flags().StringSliceVarP(&opts.reviewers, "reviewer", "r", nil, "Re-request these reviewers instead of selecting them; \"@group\" names a group from config.yaml")
+	cmd.Flags().BoolVar(&opts.includeNew, "include-new", false, "Expand \"@group\" to every member, not only those who took part in the PR")
+	cmd.Flags().StringVar(&opts.strategy, "strategy", "", "Select reviewers automatically: \"all\", \"stale\" (reviewed an older commit) or \"substitute\" (hand unavailable reviewers' requests to their backups)")
+	cmd.Flags().BoolVar(&opts.includeBusy, "include-busy", false, "Let --strategy select reviewers whose GitHub status is marked busy")
+	cmd.Flags().BoolVar(&opts.handoffComment, "handoff-comment", false, "With --strategy substitute, comment on the PR about each handoff")
+	cmd.Flags().BoolVar(&opts.confirmDefault, "confirm-default", false, "Answer yes when the confirmation is submitted empty with Enter")
+	cmd.Flags().DurationVar(&opts.confirmTimeout, "confirm-timeout", time.Minute, "Give up if the confirmation is not answered in time (0 waits forever)")
+	return cmd
+}
 
-	if err := cmd.Execute(); err != nil {
+func main() {
+	// Ctrl-C and SIGTERM cancel in-flight API calls and prompts instead of killing the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	executed, err := newRootCmd().ExecuteContextC(ctx)
+	stop()
+	if err != nil {
+		// resume saves the command it ran itself
+		if executed == nil || executed.Name() != "resume" {
+			saveResumeState(os.Args[1:], err)
+		}
 		os.Exit(1)
 	}
 }
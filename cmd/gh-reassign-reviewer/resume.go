@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ci"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/resume"
+	"github.com/spf13/cobra"
+)
+
+// resumeOptions holds the flags of the resume subcommand
+type resumeOptions struct {
+	wait    bool
+	discard bool
+}
+
+func newResumeCmd() *cobra.Command {
+	var opts resumeOptions
+
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Run the command stopped by the API rate limit again",
+		Long: "When the rate limit runs out for longer than a minute, the command is saved with its working directory. " +
+			"resume runs it again once the quota is back; requesting or removing a review is idempotent, " +
+			"so the work already done is not repeated.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			return runResume(ctx, opts)
+		},
+		SilenceUsage: true,
+	}
+	cmd.Flags().BoolVar(&opts.wait, "wait", false, "Wait for the quota to come back instead of failing")
+	cmd.Flags().BoolVar(&opts.discard, "discard", false, "Forget the saved command without running it")
+	return cmd
+}
+
+func runResume(ctx context.Context, opts resumeOptions) error {
+	store := resumeStore()
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("nothing to resume: no command stopped by the rate limit is saved in %s", store.Path())
+	}
+	if opts.discard {
+		if err := store.Clear(); err != nil {
+			return err
+		}
+		fmt.Printf("Discarded: gh reassign-reviewer %s\n", strings.Join(state.Args, " "))
+		return nil
+	}
+
+	if !state.Ready(time.Now()) {
+		if !opts.wait {
+			return fmt.Errorf("the rate limit resets at %s; resume after that or pass --wait", state.ResetAt.Local().Format("15:04:05"))
+		}
+		fmt.Printf("Waiting until %s for the rate limit to reset\n", state.ResetAt.Local().Format("15:04:05"))
+		timer := time.NewTimer(time.Until(state.ResetAt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	// A PR number refers to the repository of the directory the command ran in
+	if err := os.Chdir(state.Dir); err != nil {
+		return fmt.Errorf("failed to enter %s: %w", state.Dir, err)
+	}
+	fmt.Printf("Resuming: gh reassign-reviewer %s\n", strings.Join(state.Args, " "))
+
+	root := newRootCmd()
+	root.SetArgs(state.Args)
+	root.SilenceErrors = true
+	if err := root.ExecuteContext(ctx); err != nil {
+		saveResumeState(state.Args, err)
+		return err
+	}
+	return store.Clear()
+}
+
+// saveResumeState saves args when err is the rate limit running out, so that resume can run them again.
+// Nothing is saved in CI, where the workflow is re-run instead.
+func saveResumeState(args []string, err error) {
+	var rateLimitErr *github.RateLimitError
+	if !errors.As(err, &rateLimitErr) || ci.FromEnv(os.Getenv).Enabled {
+		return
+	}
+
+	dir, wdErr := os.Getwd()
+	if wdErr != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save the command for resume: %v\n", wdErr)
+		return
+	}
+	state := resume.State{Args: args, Dir: dir, ResetAt: rateLimitErr.ResetAt, SavedAt: time.Now()}
+	if err := resumeStore().Save(state); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save the command for resume: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Run `gh reassign-reviewer resume` after %s to continue\n", rateLimitErr.ResetAt.Local().Format("15:04:05"))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ci"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/webhook"
+	"github.com/spf13/cobra"
+)
+
+// serveOptions holds the flags of the serve subcommand
+type serveOptions struct {
+	addr  string
+	queue webhook.QueueOptions
+}
+
+func newServeCmd() *cobra.Command {
+	var opts serveOptions
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a webhook server that re-requests reviews for every repository sending events",
+		Long: "Receives pull_request (synchronize) and pull_request_review (dismissed) webhooks, " +
+			"verifies them with the secret in GH_REASSIGN_WEBHOOK_SECRET and re-requests reviewers in the background.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// --timeout bounds each job rather than the lifetime of the server
+			opts.queue.Timeout = global.timeout
+			return runServe(cmd.Context(), opts)
+		},
+		SilenceUsage: true,
+	}
+	cmd.Flags().StringVar(&opts.addr, "addr", ":8080", "Address to listen on")
+	cmd.Flags().IntVar(&opts.queue.Workers, "workers", 4, "Number of jobs processed concurrently")
+	cmd.Flags().IntVar(&opts.queue.Size, "queue-size", 100, "Number of jobs waiting before deliveries are rejected")
+	cmd.Flags().IntVar(&opts.queue.MaxAttempts, "max-attempts", 3, "Number of attempts for each job")
+	cmd.Flags().DurationVar(&opts.queue.Backoff, "retry-backoff", 5*time.Second, "Delay before the first retry, doubled on each attempt")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, opts serveOptions) error {
+	secret := os.Getenv("GH_REASSIGN_WEBHOOK_SECRET")
+	if secret == "" {
+		return fmt.Errorf("GH_REASSIGN_WEBHOOK_SECRET must be set to verify webhook signatures")
+	}
+
+	env := ci.FromEnv(os.Getenv)
+	client, err := newGitHubClient(env.Host, env.Token)
+	if err != nil {
+		return err
+	}
+
+	serviceOpts, err := serviceOptions()
+	if err != nil {
+		return err
+	}
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	queue := webhook.NewQueue(opts.queue, webhook.NewReassignHandler(client, logger, serviceOpts...), logger)
+	deduper := webhook.NewDeduper(24 * time.Hour)
+
+	server := &http.Server{
+		Addr:              opts.addr,
+		Handler:           webhook.NewServer([]byte(secret), deduper, queue, logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Printf("listening on %s", opts.addr)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		_ = queue.Close(context.Background())
+		return fmt.Errorf("webhook server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	logger.Printf("shutting down, waiting for queued jobs")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down webhook server: %w", err)
+	}
+	if err := queue.Close(shutdownCtx); err != nil {
+		return fmt.Errorf("gave up waiting for queued jobs: %w", err)
+	}
+	return nil
+}
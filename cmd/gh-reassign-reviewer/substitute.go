@@ -0,0 +1,35 @@
+package main
+
+import (
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+	"github.com/spf13/cobra"
+)
+
+func newSubstituteCmd() *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   "substitute [PR number | PR URL]",
+		Short: "Hand unavailable reviewers' pending requests over to their backups",
+		Long: "Removes the pending review request of each absent or busy reviewer and requests their first available backup, " +
+			"as configured under backups in config.yaml. Pass --for to hand off specific reviewers instead.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			opts.strategy = string(service.StrategySubstitute)
+			return runCommand(ctx, args, opts)
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringSliceVar(&opts.reviewers, "for", nil, "Hand off these reviewers' requests even if they are available; @group names the members of a group")
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Hand off without asking for confirmation")
+	cmd.Flags().BoolVar(&opts.handoffComment, "comment", false, "Comment on the PR about each handoff")
+	cmd.Flags().BoolVar(&opts.includeBusy, "include-busy", false, "Do not hand off reviewers whose GitHub status is marked busy")
+	cmd.Flags().BoolVar(&opts.confirmDefault, "confirm-default", false, "Answer yes when the confirmation is submitted empty with Enter")
+	cmd.Flags().DurationVar(&opts.confirmTimeout, "confirm-timeout", time.Minute, "Give up if the confirmation is not answered in time (0 waits forever)")
+	return cmd
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ci"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+func newUndoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "undo",
+		Short: "Remove the review requests added by the most recent action",
+		Long: "Reads the most recent action from the audit log and removes the review requests it added. " +
+			"Refuses when a reviewer is no longer requested or has reviewed since.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := commandContext(cmd)
+			defer cancel()
+			return runUndo(ctx)
+		},
+		SilenceUsage: true,
+	}
+}
+
+func runUndo(ctx context.Context) error {
+	log := auditLog()
+	entry, err := log.Last()
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return fmt.Errorf("nothing to undo: no review requests recorded in %s", log.Path())
+	}
+
+	repo, err := ci.ParseRepository(entry.Repo)
+	if err != nil {
+		return fmt.Errorf("invalid repository in audit log: %w", err)
+	}
+
+	// The action is undone on the host it was made on, whatever --hostname or GH_HOST say now
+	host := entry.Host
+	if host == "" {
+		host = resolveHost("")
+	}
+	client, err := newGitHubClientFor(host, "")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Undoing the request for %s on %s#%d at %s\n",
+		strings.Join(entry.Reviewers, ", "), entry.Repo, entry.PR, entry.Time.Local().Format("2006-01-02 15:04"))
+
+	reassignService := service.NewReassignService(client, repo, &ui.NonInteractivePrompter{}, service.WithAuditLog(log))
+	plan, err := reassignService.Undo(ctx, *entry)
+	if err != nil {
+		return err
+	}
+
+	if len(entry.Removed) > 0 {
+		fmt.Printf("Requested again: %v\n", entry.Removed)
+	}
+	fmt.Printf("Removed review requests: %v\n", plan.Reviewers)
+	return nil
+}
@@ -0,0 +1,192 @@
+// Package audit keeps an append-only JSONL record of every review request sent
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mode tells how the reviewers were chosen
+type Mode string
+
+const (
+	// ModeInteractive means the user picked the reviewers
+	ModeInteractive Mode = "interactive"
+	// ModeAuto means a strategy or automation picked the reviewers
+	ModeAuto Mode = "auto"
+)
+
+// Action tells what was done to the review requests
+type Action string
+
+const (
+	// ActionRequest means reviewers were (re-)requested; entries without an action are requests
+	ActionRequest Action = "request"
+	// ActionSubstitute means backups were requested and the requests of the reviewers they stand in for removed
+	ActionSubstitute Action = "substitute"
+	// ActionUndo means review requests added by an earlier action were removed
+	ActionUndo Action = "undo"
+)
+
+const (
+	// OutcomeSuccess is recorded when GitHub accepted the review request
+	OutcomeSuccess = "success"
+	// OutcomeFailure is recorded when the review request failed
+	OutcomeFailure = "failure"
+)
+
+// Entry is one line of the audit log
+type Entry struct {
+	Time      time.Time `json:"timestamp"`
+	Actor     string    `json:"actor"`
+	Host      string    `json:"host,omitempty"`
+	Repo      string    `json:"repo"`
+	PR        int       `json:"pr"`
+	Reviewers []string  `json:"reviewers"`
+	// Added lists the reviewers that were not already pending before the action; only they are removed
+	// by an undo, and for an undo they are the handed-off reviewers requested again.
+	// It is null for entries written before it was recorded.
+	Added []string `json:"added"`
+	// Removed lists the review requests a substitution removed; an undo requests them again
+	Removed []string `json:"removed,omitempty"`
+	Mode    Mode     `json:"mode"`
+	Action  Action   `json:"action,omitempty"`
+	Outcome string   `json:"outcome"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Recorder stores audit entries
+type Recorder interface {
+	Record(entry Entry) error
+}
+
+// Log is an audit log backed by a JSONL file
+type Log struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLog returns the log stored at path; the file is created on the first write
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// Path returns the file backing the log
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Record appends entry as a single line
+func (l *Log) Record(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	// A single write keeps concurrent appends from interleaving
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return f.Close()
+}
+
+// Read returns the entries matching filter, oldest first; a missing log has no entries
+func (l *Log) Read(filter Filter) ([]Entry, error) {
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log line %d: %w", line, err)
+		}
+		if filter.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Last returns the most recent successful entry, or nil when there is none
+func (l *Log) Last() (*Entry, error) {
+	entries, err := l.Read(Filter{})
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Outcome == OutcomeSuccess {
+			return &entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// IsUndo reports whether the entry removed review requests
+func (e Entry) IsUndo() bool {
+	return e.Action == ActionUndo
+}
+
+// Filter selects audit entries; zero fields match everything
+type Filter struct {
+	Repo     string
+	PR       int
+	Reviewer string
+	Since    time.Time // inclusive
+	Until    time.Time // exclusive
+}
+
+// Match reports whether entry satisfies every set field
+func (f Filter) Match(entry Entry) bool {
+	switch {
+	case f.Repo != "" && !strings.EqualFold(entry.Repo, f.Repo):
+		return false
+	case f.PR != 0 && entry.PR != f.PR:
+		return false
+	case !f.Since.IsZero() && entry.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !entry.Time.Before(f.Until):
+		return false
+	}
+	if f.Reviewer == "" {
+		return true
+	}
+	for _, reviewer := range entry.Reviewers {
+		if strings.EqualFold(reviewer, f.Reviewer) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEntries() []Entry {
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	return []Entry{
+		{Time: day, Actor: "me", Repo: "owner/repo", PR: 1, Reviewers: []string{"alice"}, Mode: ModeInteractive, Outcome: OutcomeSuccess},
+		{Time: day.AddDate(0, 0, 1), Actor: "me", Repo: "owner/repo", PR: 2, Reviewers: []string{"bob", "carol"}, Mode: ModeAuto, Outcome: OutcomeSuccess},
+		{Time: day.AddDate(0, 0, 2), Actor: "me", Repo: "owner/other", PR: 1, Reviewers: []string{"alice"}, Mode: ModeAuto, Outcome: OutcomeFailure, Error: "API error"},
+	}
+}
+
+// TestLog_RecordAndRead tests that entries are appended one per line and read back in order
+func TestLog_RecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "audit.jsonl")
+	log := NewLog(path)
+
+	for _, entry := range testEntries() {
+		if err := log.Record(entry); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected log file, got %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("Expected 3 lines, got %d", lines)
+	}
+
+	entries, err := log.Read(Filter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(entries) != 3 || entries[2].Error != "API error" || entries[1].Reviewers[1] != "carol" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+// TestLog_Last tests that the most recent successful entry is returned
+func TestLog_Last(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+
+	if last, err := log.Last(); err != nil || last != nil {
+		t.Fatalf("Expected no entry, got %+v, %v", last, err)
+	}
+
+	for _, entry := range testEntries() {
+		if err := log.Record(entry); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// The failed request at the end is skipped
+	last, err := log.Last()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if last == nil || last.PR != 2 || last.IsUndo() {
+		t.Errorf("Expected the request on #2, got %+v", last)
+	}
+}
+
+// TestLog_ReadMissing tests that a log that was never written has no entries
+func TestLog_ReadMissing(t *testing.T) {
+	entries, err := NewLog(filepath.Join(t.TempDir(), "audit.jsonl")).Read(Filter{})
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries and no error, got %v, %v", entries, err)
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []int // indexes of testEntries
+	}{
+		{name: "no filter", filter: Filter{}, expected: []int{0, 1, 2}},
+		{name: "repo is case-insensitive", filter: Filter{Repo: "Owner/Repo"}, expected: []int{0, 1}},
+		{name: "PR", filter: Filter{PR: 1}, expected: []int{0, 2}},
+		{name: "reviewer", filter: Filter{Reviewer: "carol"}, expected: []int{1}},
+		{name: "since is inclusive", filter: Filter{Since: day.AddDate(0, 0, 1).Add(9 * time.Hour)}, expected: []int{1, 2}},
+		{name: "until is exclusive", filter: Filter{Until: day.AddDate(0, 0, 2)}, expected: []int{0, 1}},
+		{name: "combined", filter: Filter{Repo: "owner/repo", Reviewer: "alice"}, expected: []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for i, entry := range testEntries() {
+				if tt.filter.Match(entry) {
+					got = append(got, i)
+				}
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Expected %v, got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+}
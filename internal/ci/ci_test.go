@@ -0,0 +1,166 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+func envFrom(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name          string
+		vars          map[string]string
+		expectEnabled bool
+		expectToken   string
+		expectHost    string
+	}{
+		{name: "not CI", vars: map[string]string{}, expectEnabled: false},
+		{name: "CI", vars: map[string]string{"CI": "true"}, expectEnabled: true},
+		{name: "GitHub Actions", vars: map[string]string{"GITHUB_ACTIONS": "true"}, expectEnabled: true},
+		{name: "CI false", vars: map[string]string{"CI": "false"}, expectEnabled: false},
+		{
+			name:          "GH_TOKEN takes precedence",
+			vars:          map[string]string{"CI": "true", "GH_TOKEN": "gh", "GITHUB_TOKEN": "github"},
+			expectEnabled: true,
+			expectToken:   "gh",
+		},
+		{
+			name:          "GITHUB_TOKEN fallback",
+			vars:          map[string]string{"CI": "true", "GITHUB_TOKEN": "github"},
+			expectEnabled: true,
+			expectToken:   "github",
+		},
+		{
+			name:          "enterprise server",
+			vars:          map[string]string{"CI": "true", "GITHUB_SERVER_URL": "https://ghe.example.com"},
+			expectEnabled: true,
+			expectHost:    "ghe.example.com",
+		},
+		{
+			name:          "github.com server",
+			vars:          map[string]string{"CI": "true", "GITHUB_SERVER_URL": "https://github.com"},
+			expectEnabled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := FromEnv(envFrom(tt.vars))
+			if env.Enabled != tt.expectEnabled {
+				t.Errorf("Enabled = %v, want %v", env.Enabled, tt.expectEnabled)
+			}
+			if env.Token != tt.expectToken {
+				t.Errorf("Token = %q, want %q", env.Token, tt.expectToken)
+			}
+			if env.Host != tt.expectHost {
+				t.Errorf("Host = %q, want %q", env.Host, tt.expectHost)
+			}
+		})
+	}
+}
+
+func TestParseRepository(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectError bool
+	}{
+		{input: "owner/repo"},
+		{input: "", expectError: true},
+		{input: "owner", expectError: true},
+		{input: "owner/", expectError: true},
+		{input: "owner/repo/extra", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			repo, err := ParseRepository(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if repo.GetOwner() != "owner" || repo.GetName() != "repo" {
+				t.Errorf("Unexpected repository %+v", repo)
+			}
+		})
+	}
+}
+
+func TestEnvironment_PullRequestNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		expected    int
+		expectError bool
+	}{
+		{name: "pull_request event", payload: `{"action": "synchronize", "number": 42, "pull_request": {"number": 42}}`, expected: 42},
+		{name: "pull_request_review event", payload: `{"action": "submitted", "pull_request": {"number": 7}}`, expected: 7},
+		{name: "push event", payload: `{"ref": "refs/heads/main"}`, expectError: true},
+		{name: "invalid payload", payload: `{`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "event.json")
+			if err := os.WriteFile(path, []byte(tt.payload), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Environment{EventPath: path}.PullRequestNumber()
+			if tt.expectError != (err != nil) {
+				t.Fatalf("Unexpected error result: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("PullRequestNumber() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+
+	if _, err := (Environment{}).PullRequestNumber(); err == nil {
+		t.Errorf("Expected error without GITHUB_EVENT_PATH")
+	}
+}
+
+func TestEnvironment_WriteResult(t *testing.T) {
+	dir := t.TempDir()
+	env := Environment{
+		StepSummary: filepath.Join(dir, "summary.md"),
+		Output:      filepath.Join(dir, "output"),
+	}
+	plan := &models.ReassignPlan{Owner: "owner", Repo: "repo", PRNumber: 42, Reviewers: []string{"alice", "bob"}}
+
+	if err := env.WriteResult(plan, "owner/repo", 42); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	summary, _ := os.ReadFile(env.StepSummary)
+	if !strings.Contains(string(summary), "owner/repo#42") || !strings.Contains(string(summary), "- @bob") {
+		t.Errorf("Unexpected summary: %q", summary)
+	}
+
+	output, _ := os.ReadFile(env.Output)
+	expected := "pr-number=42\nrequested=true\nreviewers=alice,bob\n"
+	if string(output) != expected {
+		t.Errorf("Outputs = %q, want %q", output, expected)
+	}
+}
+
+func TestFormatOutputs_NothingRequested(t *testing.T) {
+	expected := "pr-number=42\nrequested=false\nreviewers=\n"
+	if got := FormatOutputs(nil, 42); got != expected {
+		t.Errorf("FormatOutputs() = %q, want %q", got, expected)
+	}
+}
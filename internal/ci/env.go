@@ -0,0 +1,84 @@
+package ci
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// Environment holds the settings GitHub Actions passes through environment variables
+type Environment struct {
+	Enabled     bool
+	EventPath   string
+	Repository  string
+	Token       string
+	StepSummary string
+	Output      string
+	Host        string // host of GITHUB_SERVER_URL, set when running on GitHub Enterprise Server
+}
+
+// FromEnv reads the CI environment using getenv, typically os.Getenv
+func FromEnv(getenv func(string) string) Environment {
+	token := getenv("GH_TOKEN")
+	if token == "" {
+		token = getenv("GITHUB_TOKEN")
+	}
+
+	return Environment{
+		Enabled:     getenv("CI") == "true" || getenv("GITHUB_ACTIONS") == "true",
+		EventPath:   getenv("GITHUB_EVENT_PATH"),
+		Repository:  getenv("GITHUB_REPOSITORY"),
+		Token:       token,
+		StepSummary: getenv("GITHUB_STEP_SUMMARY"),
+		Output:      getenv("GITHUB_OUTPUT"),
+		Host:        serverHost(getenv("GITHUB_SERVER_URL")),
+	}
+}
+
+// serverHost returns the host of an enterprise server URL, or "" for github.com
+func serverHost(serverURL string) string {
+	u, err := url.Parse(serverURL)
+	if err != nil || u.Hostname() == "" || !github.IsEnterprise(u.Hostname()) {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// RepositoryInfo parses GITHUB_REPOSITORY ("owner/name")
+func (e Environment) RepositoryInfo() (*github.Repository, error) {
+	return ParseRepository(e.Repository)
+}
+
+// Event reads the event payload at GITHUB_EVENT_PATH
+func (e Environment) Event() (*models.PullRequestEvent, error) {
+	if e.EventPath == "" {
+		return nil, fmt.Errorf("GITHUB_EVENT_PATH is not set; pass the PR number as an argument")
+	}
+	return ReadEventFile(e.EventPath)
+}
+
+// PullRequestNumber reads the PR number from the event payload at GITHUB_EVENT_PATH
+func (e Environment) PullRequestNumber() (int, error) {
+	event, err := e.Event()
+	if err != nil {
+		return 0, err
+	}
+
+	number := event.PullRequestNumber()
+	if number == 0 {
+		return 0, fmt.Errorf("event payload does not reference a pull request; pass the PR number as an argument")
+	}
+	return number, nil
+}
+
+// ParseRepository parses an "owner/name" repository reference
+func ParseRepository(fullName string) (*github.Repository, error) {
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid repository %q: expected owner/name", fullName)
+	}
+	return &github.Repository{Owner: owner, Name: name}, nil
+}
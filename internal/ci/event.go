@@ -0,0 +1,30 @@
+package ci
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// ReadEvent decodes an event payload
+func ReadEvent(r io.Reader) (*models.PullRequestEvent, error) {
+	var event models.PullRequestEvent
+	if err := json.NewDecoder(r).Decode(&event); err != nil {
+		return nil, fmt.Errorf("failed to decode event payload: %w", err)
+	}
+	return &event, nil
+}
+
+// ReadEventFile decodes the event payload stored at path
+func ReadEventFile(path string) (*models.PullRequestEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event payload: %w", err)
+	}
+	defer f.Close()
+
+	return ReadEvent(f)
+}
@@ -0,0 +1,69 @@
+package ci
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// WriteResult reports the outcome to $GITHUB_STEP_SUMMARY and the step outputs.
+// A nil plan means no review was requested.
+func (e Environment) WriteResult(plan *models.ReassignPlan, repo string, prNumber int) error {
+	if e.StepSummary != "" {
+		if err := appendFile(e.StepSummary, FormatSummary(plan, repo, prNumber)); err != nil {
+			return fmt.Errorf("failed to write step summary: %w", err)
+		}
+	}
+
+	if e.Output != "" {
+		if err := appendFile(e.Output, FormatOutputs(plan, prNumber)); err != nil {
+			return fmt.Errorf("failed to write step outputs: %w", err)
+		}
+	}
+	return nil
+}
+
+// FormatSummary renders the Markdown written to the job summary
+func FormatSummary(plan *models.ReassignPlan, repo string, prNumber int) string {
+	var b strings.Builder
+	b.WriteString("### Reassign reviewer\n\n")
+	if plan == nil || len(plan.Reviewers) == 0 {
+		fmt.Fprintf(&b, "No reviewers needed to be re-requested on %s#%d.\n", repo, prNumber)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Re-requested review on %s/%s#%d from:\n\n", plan.Owner, plan.Repo, plan.PRNumber)
+	for _, reviewer := range plan.Reviewers {
+		fmt.Fprintf(&b, "- @%s\n", reviewer)
+	}
+	return b.String()
+}
+
+// FormatOutputs renders the step outputs in the $GITHUB_OUTPUT format
+func FormatOutputs(plan *models.ReassignPlan, prNumber int) string {
+	var reviewers []string
+	if plan != nil {
+		reviewers = plan.Reviewers
+	}
+
+	return fmt.Sprintf(
+		"pr-number=%d\nrequested=%s\nreviewers=%s\n",
+		prNumber,
+		strconv.FormatBool(len(reviewers) > 0),
+		strings.Join(reviewers, ","),
+	)
+}
+
+func appendFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(content)
+	return err
+}
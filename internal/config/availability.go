@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// AbsenceEntry is one absence in a YAML availability file. Dates are inclusive and
+// may be plain dates (YYYY-MM-DD, local time) or RFC 3339 timestamps.
+type AbsenceEntry struct {
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	Reason string `yaml:"reason"`
+}
+
+// LoadAvailability reads an availability file, choosing the format from its extension
+func LoadAvailability(path string) (models.Availability, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read availability file: %w", err)
+	}
+
+	var availability models.Availability
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ics", ".ical":
+		availability, err = ParseICS(string(data), time.Local)
+	default:
+		var entries map[string][]AbsenceEntry
+		if err = yaml.Unmarshal(data, &entries); err == nil {
+			availability, err = parseAbsenceEntries(entries)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid availability file %s: %w", path, err)
+	}
+	return availability, nil
+}
+
+func parseAbsenceEntries(entries map[string][]AbsenceEntry) (models.Availability, error) {
+	availability := models.Availability{}
+	for login, list := range entries {
+		for _, entry := range list {
+			absence, err := entry.absence()
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", login, err)
+			}
+			key := strings.ToLower(login)
+			availability[key] = append(availability[key], absence)
+		}
+	}
+	return availability, nil
+}
+
+func (e AbsenceEntry) absence() (models.Absence, error) {
+	from, _, err := ParseDate(e.From)
+	if err != nil {
+		return models.Absence{}, fmt.Errorf("invalid from: %w", err)
+	}
+
+	to, dateOnly, err := ParseDate(e.To)
+	if e.To == "" {
+		// A single day
+		to, dateOnly, err = from, true, nil
+	}
+	if err != nil {
+		return models.Absence{}, fmt.Errorf("invalid to: %w", err)
+	}
+	// A plain end date includes the whole day
+	if dateOnly {
+		to = to.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return models.Absence{}, fmt.Errorf("to (%s) is before from (%s)", e.To, e.From)
+	}
+
+	return models.Absence{From: from, To: to, Reason: e.Reason}, nil
+}
+
+// ParseDate accepts a local date or an RFC 3339 timestamp and reports whether it was a plain date
+func ParseDate(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("expected YYYY-MM-DD or RFC 3339, got %q", value)
+	}
+	return t, false, nil
+}
@@ -0,0 +1,74 @@
+// Package config reads the user's configuration file
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file inside the config directory
+const FileName = "config.yaml"
+
+// Config is the content of config.yaml
+type Config struct {
+	// AvailabilityFile is a YAML or iCalendar (.ics) file of absences, relative to the config file
+	AvailabilityFile string `yaml:"availability"`
+	// Absences lists absences inline, in the same format as a YAML availability file
+	Absences map[string][]AbsenceEntry `yaml:"absences"`
+	// Backups maps a reviewer to the reviewers who stand in for them, in order of preference
+	Backups map[string][]string `yaml:"backups"`
+	// Groups names sets of reviewers that --reviewer accepts as "@group"
+	Groups map[string][]string `yaml:"groups"`
+	// Cooldown is the default of --cooldown, e.g. 12h
+	Cooldown time.Duration `yaml:"cooldown"`
+
+	// Availability merges Absences and the absences read from AvailabilityFile
+	Availability models.Availability `yaml:"-"`
+}
+
+// Load reads the configuration at path; a missing file yields an empty configuration
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		cfg.Availability = models.Availability{}
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if cfg.Cooldown < 0 {
+		return nil, fmt.Errorf("invalid cooldown in %s: must not be negative", path)
+	}
+
+	cfg.Availability, err = parseAbsenceEntries(cfg.Absences)
+	if err != nil {
+		return nil, fmt.Errorf("invalid absences in %s: %w", path, err)
+	}
+
+	if cfg.AvailabilityFile != "" {
+		file := cfg.AvailabilityFile
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+		availability, err := LoadAvailability(file)
+		if err != nil {
+			return nil, err
+		}
+		for login, absences := range availability {
+			cfg.Availability[login] = append(cfg.Availability[login], absences...)
+		}
+	}
+
+	return cfg, nil
+}
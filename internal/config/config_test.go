@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// TestLoad tests reading config.yaml with inline absences, an availability file and backups
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	writeFile(t, path, `
+availability: away.yaml
+absences:
+  Alice:
+    - from: 2024-05-06
+      to: 2024-05-10
+      reason: Vacation
+backups:
+  alice: [bob, carol]
+groups:
+  backend: [alice, bob]
+cooldown: 12h
+`)
+	writeFile(t, filepath.Join(dir, "away.yaml"), `
+bob:
+  - from: 2024-05-08
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		login  string
+		at     time.Time
+		absent bool
+	}{
+		{login: "alice", at: time.Date(2024, 5, 6, 0, 0, 0, 0, time.Local), absent: true},
+		{login: "ALICE", at: time.Date(2024, 5, 10, 23, 59, 0, 0, time.Local), absent: true},
+		{login: "alice", at: time.Date(2024, 5, 11, 0, 0, 0, 0, time.Local), absent: false},
+		{login: "bob", at: time.Date(2024, 5, 8, 12, 0, 0, 0, time.Local), absent: true},
+		{login: "bob", at: time.Date(2024, 5, 9, 0, 0, 0, 0, time.Local), absent: false},
+		{login: "carol", at: time.Date(2024, 5, 8, 12, 0, 0, 0, time.Local), absent: false},
+	}
+	for _, tt := range tests {
+		if _, absent := cfg.Availability.AbsentAt(tt.login, tt.at); absent != tt.absent {
+			t.Errorf("Expected %s absent at %s to be %v", tt.login, tt.at, tt.absent)
+		}
+	}
+
+	if absence, _ := cfg.Availability.AbsentAt("alice", time.Date(2024, 5, 7, 0, 0, 0, 0, time.Local)); absence.Reason != "Vacation" {
+		t.Errorf("Expected reason Vacation, got %q", absence.Reason)
+	}
+	if backups := cfg.Backups["alice"]; len(backups) != 2 || backups[0] != "bob" {
+		t.Errorf("Expected backups [bob carol], got %v", backups)
+	}
+	if members := cfg.Groups["backend"]; len(members) != 2 {
+		t.Errorf("Expected group backend [alice bob], got %v", members)
+	}
+	if cfg.Cooldown != 12*time.Hour {
+		t.Errorf("Expected a cooldown of 12h, got %s", cfg.Cooldown)
+	}
+}
+
+// TestLoadMissing tests that a missing config file is not an error
+func TestLoadMissing(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), FileName))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cfg.Availability) != 0 || len(cfg.Backups) != 0 {
+		t.Errorf("Expected an empty config, got %+v", cfg)
+	}
+}
+
+// TestLoadInvalid tests that malformed absences are reported
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "bad date", content: "absences:\n  alice:\n    - from: May 6\n"},
+		{name: "end before start", content: "absences:\n  alice:\n    - from: 2024-05-10\n      to: 2024-05-06\n"},
+		{name: "missing availability file", content: "availability: missing.ics\n"},
+		{name: "bad cooldown", content: "cooldown: twelve hours\n"},
+		{name: "negative cooldown", content: "cooldown: -1h\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), FileName)
+			writeFile(t, path, tt.content)
+			if _, err := Load(path); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
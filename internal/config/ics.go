@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// ParseICS reads absences from the VEVENTs of an iCalendar file. The login of each event comes from
+// an X-GITHUB-LOGIN property, or otherwise from the SUMMARY text before the first colon
+// ("alice: Vacation"). Floating times and all-day dates are read in loc. An event ends at DTEND
+// or after its DURATION.
+// Recurring absences (RRULE) are rejected rather than read as their first occurrence only.
+func ParseICS(data string, loc *time.Location) (models.Availability, error) {
+	availability := models.Availability{}
+
+	var event map[string]icsProperty
+	for i, line := range unfoldICS(data) {
+		name, prop := parseICSLine(line)
+		switch {
+		case name == "BEGIN" && prop.value == "VEVENT":
+			event = make(map[string]icsProperty)
+		case name == "END" && prop.value == "VEVENT":
+			if event == nil {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN", i+1)
+			}
+			login, absence, err := icsAbsence(event, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if login != "" {
+				availability[login] = append(availability[login], absence)
+			}
+			event = nil
+		case event != nil:
+			event[name] = prop
+		}
+	}
+	return availability, nil
+}
+
+type icsProperty struct {
+	params map[string]string
+	value  string
+}
+
+// unfoldICS joins continuation lines, which start with a space or a tab
+func unfoldICS(data string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// parseICSLine splits "NAME;PARAM=VALUE:value" into its parts
+func parseICSLine(line string) (string, icsProperty) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	prop := icsProperty{params: make(map[string]string), value: strings.TrimSpace(value)}
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = val
+	}
+	return strings.ToUpper(parts[0]), prop
+}
+
+func icsAbsence(event map[string]icsProperty, loc *time.Location) (string, models.Absence, error) {
+	summary := unescapeICS(event["SUMMARY"].value)
+	login, reason := strings.TrimSpace(event["X-GITHUB-LOGIN"].value), summary
+	if login == "" {
+		var ok bool
+		login, reason, ok = strings.Cut(summary, ":")
+		if !ok {
+			// Not addressed to anyone
+			return "", models.Absence{}, nil
+		}
+	}
+	login = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(login), "@"))
+	if _, ok := event["RRULE"]; ok {
+		return "", models.Absence{}, fmt.Errorf("event %q repeats; recurring absences are not supported, list each one instead", summary)
+	}
+
+	start, ok := event["DTSTART"]
+	if !ok {
+		return "", models.Absence{}, fmt.Errorf("event %q has no DTSTART", summary)
+	}
+	from, allDay, err := parseICSTime(start, loc)
+	if err != nil {
+		return "", models.Absence{}, err
+	}
+
+	// DTEND is exclusive; without it or a DURATION an all-day event lasts one day
+	to := from.AddDate(0, 0, 1)
+	if !allDay {
+		to = from
+	}
+	end, hasEnd := event["DTEND"]
+	duration, hasDuration := event["DURATION"]
+	switch {
+	case hasEnd && hasDuration:
+		return "", models.Absence{}, fmt.Errorf("event %q has both DTEND and DURATION", summary)
+	case hasEnd:
+		if to, _, err = parseICSTime(end, loc); err != nil {
+			return "", models.Absence{}, err
+		}
+		if to.Before(from) {
+			return "", models.Absence{}, fmt.Errorf("event %q ends (%s) before it starts (%s)", summary, end.value, start.value)
+		}
+	case hasDuration:
+		if to, err = addICSDuration(from, duration.value); err != nil {
+			return "", models.Absence{}, fmt.Errorf("event %q: %w", summary, err)
+		}
+	}
+
+	return login, models.Absence{From: from, To: to, Reason: strings.TrimSpace(reason)}, nil
+}
+
+// parseICSTime reads a DATE or DATE-TIME value and reports whether it was a date
+func parseICSTime(prop icsProperty, loc *time.Location) (time.Time, bool, error) {
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(tzid); err == nil {
+			loc = tz
+		}
+	}
+
+	switch {
+	case prop.params["VALUE"] == "DATE" || len(prop.value) == len("20060102"):
+		t, err := time.ParseInLocation("20060102", prop.value, loc)
+		return t, true, err
+	case strings.HasSuffix(prop.value, "Z"):
+		t, err := time.Parse("20060102T150405Z", prop.value)
+		return t, false, err
+	default:
+		t, err := time.ParseInLocation("20060102T150405", prop.value, loc)
+		return t, false, err
+	}
+}
+
+// icsDurationRE matches an RFC 5545 DURATION such as P2W, P1D, PT8H or P1DT12H30M
+var icsDurationRE = regexp.MustCompile(`^\+?P(?:(\d+)W|(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?)$`)
+
+// addICSDuration adds a DURATION to t; weeks and days are calendar days, so they keep the time of day across DST.
+// Negative durations, which would end an absence before it starts, are rejected.
+func addICSDuration(t time.Time, value string) (time.Time, error) {
+	match := icsDurationRE.FindStringSubmatch(value)
+	if match == nil || strings.HasSuffix(value, "P") || strings.HasSuffix(value, "T") {
+		return time.Time{}, fmt.Errorf("invalid DURATION %q: expected e.g. P1D or PT8H", value)
+	}
+
+	n := make([]int, len(match))
+	for i, part := range match[1:] {
+		if part != "" {
+			n[i+1], _ = strconv.Atoi(part)
+		}
+	}
+	weeks, days, hours, minutes, seconds := n[1], n[2], n[3], n[4], n[5]
+	clock := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	return t.AddDate(0, 0, 7*weeks+days).Add(clock), nil
+}
+
+func unescapeICS(value string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:@alice: Vacation\\, Hawaii\r\n" +
+	"DTSTART;VALUE=DATE:20240506\r\n" +
+	"DTEND;VALUE=DATE:20240511\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Conference\r\n" +
+	"X-GITHUB-LOGIN:Bob\r\n" +
+	"DTSTART:20240508T090000Z\r\n" +
+	"DTEND:20240508T170000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:carol: Half day\r\n" +
+	"DTSTART;TZID=Asia/Tokyo:20240509T1300\r\n" +
+	" 00\r\n" +
+	"DTEND;TZID=Asia/Tokyo:20240509T180000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:Team offsite\r\n" +
+	"DTSTART;VALUE=DATE:20240520\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:alice: Training\r\n" +
+	"DTSTART;VALUE=DATE:20240527\r\n" +
+	"DURATION:P1W\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"SUMMARY:bob: Dentist\r\n" +
+	"DTSTART:20240530T090000Z\r\n" +
+	"DURATION:PT2H30M\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+// TestParseICS tests reading absences from all-day, UTC, TZID, folded and DURATION events
+func TestParseICS(t *testing.T) {
+	availability, err := ParseICS(testCalendar, time.UTC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(availability) != 3 {
+		t.Fatalf("Expected 3 users, got %v", availability)
+	}
+
+	alice := availability["alice"]
+	if len(alice) != 2 {
+		t.Fatalf("Expected 2 absences for alice, got %v", alice)
+	}
+	if !alice[0].From.Equal(time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)) || !alice[0].To.Equal(time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected range for alice: %v - %v", alice[0].From, alice[0].To)
+	}
+	if alice[0].Reason != "Vacation, Hawaii" {
+		t.Errorf("Expected reason %q, got %q", "Vacation, Hawaii", alice[0].Reason)
+	}
+
+	if !alice[1].From.Equal(time.Date(2024, 5, 27, 0, 0, 0, 0, time.UTC)) || !alice[1].To.Equal(time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected range for alice's training: %v - %v", alice[1].From, alice[1].To)
+	}
+
+	bob := availability["bob"]
+	if len(bob) != 2 || bob[0].Reason != "Conference" || !bob[0].To.Equal(time.Date(2024, 5, 8, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected absence for bob: %v", bob)
+	}
+	if len(bob) == 2 && !bob[1].To.Equal(time.Date(2024, 5, 30, 11, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected end of bob's dentist appointment: %v", bob[1].To)
+	}
+
+	carol := availability["carol"]
+	if len(carol) != 1 || !carol[0].From.Equal(time.Date(2024, 5, 9, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected absence for carol: %v", carol)
+	}
+}
+
+// TestParseICSInvalid tests that events without a usable start or end, or that repeat, are reported
+func TestParseICSInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "no start", data: "BEGIN:VEVENT\nSUMMARY:alice: Off\nEND:VEVENT\n"},
+		{name: "bad start", data: "BEGIN:VEVENT\nSUMMARY:alice: Off\nDTSTART:tomorrow\nEND:VEVENT\n"},
+		{name: "unbalanced", data: "END:VEVENT\n"},
+		{name: "end before start", data: "BEGIN:VEVENT\nSUMMARY:alice: Off\nDTSTART:20240510\nDTEND:20240509\nEND:VEVENT\n"},
+		{name: "negative duration", data: "BEGIN:VEVENT\nSUMMARY:alice: Off\nDTSTART:20240510\nDURATION:-P1D\nEND:VEVENT\n"},
+		{name: "bad duration", data: "BEGIN:VEVENT\nSUMMARY:alice: Off\nDTSTART:20240510\nDURATION:PT\nEND:VEVENT\n"},
+		{name: "end and duration", data: "BEGIN:VEVENT\nSUMMARY:alice: Off\nDTSTART:20240510\nDTEND:20240511\nDURATION:P1D\nEND:VEVENT\n"},
+		{name: "recurring", data: "BEGIN:VEVENT\nSUMMARY:alice: Off\nDTSTART:20240510\nRRULE:FREQ=WEEKLY\nEND:VEVENT\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseICS(tt.data, time.UTC); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
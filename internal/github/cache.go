@@ -0,0 +1,216 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CacheTransport stores GET responses on disk and revalidates them with conditional requests.
+// GitHub does not count 304 responses against the rate limit.
+type CacheTransport struct {
+	Base http.RoundTripper
+	Dir  string
+	Log  io.Writer // receives cache hits; nil disables logging
+
+	now func() time.Time
+}
+
+// cacheEntry is the on-disk form of a cached response
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StoredAt     time.Time   `json:"stored_at"`
+}
+
+// compareBetweenSHAs matches comparisons of two commit SHAs, which never change
+var compareBetweenSHAs = regexp.MustCompile(`/compare/[0-9a-f]{40}\.\.\.[0-9a-f]{40}$`)
+
+// NewCacheTransport caches the responses of base under dir
+func NewCacheTransport(base http.RoundTripper, dir string, log io.Writer) *CacheTransport {
+	return &CacheTransport{Base: base, Dir: dir, Log: log}
+}
+
+// TTL returns how long a response for path may be served without asking GitHub.
+// Everything mutable is revalidated on every use; in particular requested_reviewers
+// must reflect the latest state before reviewers are re-requested.
+func TTL(path string) time.Duration {
+	path = strings.TrimPrefix(path, "/api/v3") // GitHub Enterprise Server prefix
+	switch {
+	case strings.HasSuffix(path, "/requested_reviewers"):
+		return 0
+	case compareBetweenSHAs.MatchString(path):
+		return 7 * 24 * time.Hour
+	case path == "/user":
+		return time.Hour
+	default:
+		return 0
+	}
+}
+
+func (t *CacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		// A write makes any cached read of the same resource stale
+		t.remove(t.key(req, http.MethodGet))
+		return t.Base.RoundTrip(req)
+	}
+
+	key := t.key(req, req.Method)
+	entry := t.load(key)
+	if entry != nil && t.clock().Sub(entry.StoredAt) < TTL(req.URL.Path) {
+		t.logf("cache hit: %s", req.URL.Path)
+		return entry.response(req), nil
+	}
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		resp.Body.Close()
+		t.logf("cache revalidated: %s", req.URL.Path)
+		entry.StoredAt = t.clock()
+		t.store(key, entry)
+		return entry.response(req), nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.store(key, &cacheEntry{
+		URL:          req.URL.String(),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		Body:         body,
+		ETag:         etag,
+		LastModified: lastModified,
+		StoredAt:     t.clock(),
+	})
+	return resp, nil
+}
+
+// ClearCache removes every cached response under dir
+func ClearCache(dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// key identifies a response by method, URL and credentials so tokens never share entries
+func (t *CacheTransport) key(req *http.Request, method string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n%s\n%s", method, req.URL.String(), req.Header.Get("Authorization"), req.Header.Get("Accept"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (t *CacheTransport) path(key string) string {
+	return filepath.Join(t.Dir, key[:2], key+".json")
+}
+
+// load returns the cached entry, or nil when it is missing or unreadable
+func (t *CacheTransport) load(key string) *cacheEntry {
+	data, err := os.ReadFile(t.path(key))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store writes the entry atomically; the cache is best effort, so failures are only logged
+func (t *CacheTransport) store(key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	path := t.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.logf("cache write failed: %v", err)
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "entry-*")
+	if err != nil {
+		t.logf("cache write failed: %v", err)
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		t.logf("cache write failed: %v", err)
+	}
+}
+
+func (t *CacheTransport) remove(key string) {
+	os.Remove(t.path(key))
+}
+
+func (t *CacheTransport) logf(format string, args ...interface{}) {
+	if t.Log != nil {
+		fmt.Fprintf(t.Log, format+"\n", args...)
+	}
+}
+
+func (t *CacheTransport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,142 @@
+package github
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cachingServer fakes GitHub: it answers 304 when the ETag matches and records the requests it saw
+type cachingServer struct {
+	etag     string
+	body     string
+	requests []*http.Request
+}
+
+func (s *cachingServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+	header := http.Header{}
+	header.Set("ETag", s.etag)
+	if req.Method == http.MethodGet && req.Header.Get("If-None-Match") == s.etag {
+		return &http.Response{StatusCode: http.StatusNotModified, Header: header, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(s.body))}, nil
+}
+
+func newCacheTestTransport(t *testing.T, server *cachingServer, now *time.Time) *CacheTransport {
+	transport := NewCacheTransport(server, t.TempDir(), nil)
+	transport.now = func() time.Time { return *now }
+	return transport
+}
+
+func get(t *testing.T, transport http.RoundTripper, method, url string) string {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	req.Header.Set("Authorization", "token abc")
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+// TestCacheTransport_Revalidates tests that a cached response is revalidated and served on 304
+func TestCacheTransport_Revalidates(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	server := &cachingServer{etag: `"v1"`, body: `[{"id":1}]`}
+	transport := newCacheTestTransport(t, server, &now)
+
+	url := "https://api.github.com/repos/o/r/pulls/1/reviews"
+	if body := get(t, transport, http.MethodGet, url); body != `[{"id":1}]` {
+		t.Fatalf("Expected body from server, got %q", body)
+	}
+	if body := get(t, transport, http.MethodGet, url); body != `[{"id":1}]` {
+		t.Errorf("Expected cached body on 304, got %q", body)
+	}
+
+	if len(server.requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(server.requests))
+	}
+	if got := server.requests[1].Header.Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("Expected If-None-Match %q, got %q", `"v1"`, got)
+	}
+
+	// A changed resource replaces the cached body
+	server.etag, server.body = `"v2"`, `[{"id":2}]`
+	if body := get(t, transport, http.MethodGet, url); body != `[{"id":2}]` {
+		t.Errorf("Expected new body, got %q", body)
+	}
+}
+
+// TestCacheTransport_TTL tests which responses are served without asking GitHub
+func TestCacheTransport_TTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		requests int
+	}{
+		{"comparison of two SHAs is immutable", "https://api.github.com/repos/o/r/compare/" + strings.Repeat("a", 40) + "..." + strings.Repeat("b", 40), 1},
+		{"current user is cached for an hour", "https://api.github.com/user", 1},
+		{"requested reviewers are always revalidated", "https://api.github.com/repos/o/r/pulls/1/requested_reviewers", 2},
+		{"enterprise requested reviewers are always revalidated", "https://ghe.example.com/api/v3/repos/o/r/pulls/1/requested_reviewers", 2},
+		{"reviews are revalidated", "https://api.github.com/repos/o/r/pulls/1/reviews", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Unix(1700000000, 0)
+			server := &cachingServer{etag: `"v1"`, body: "{}"}
+			transport := newCacheTestTransport(t, server, &now)
+
+			get(t, transport, http.MethodGet, tt.url)
+			now = now.Add(time.Minute)
+			get(t, transport, http.MethodGet, tt.url)
+
+			if len(server.requests) != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, len(server.requests))
+			}
+		})
+	}
+}
+
+// TestCacheTransport_WriteInvalidates tests that a write drops the cached read of the same resource
+func TestCacheTransport_WriteInvalidates(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	server := &cachingServer{etag: `"v1"`, body: `{"users":[]}`}
+	transport := newCacheTestTransport(t, server, &now)
+
+	url := "https://api.github.com/repos/o/r/pulls/1/requested_reviewers"
+	get(t, transport, http.MethodGet, url)
+	get(t, transport, http.MethodPost, url)
+	get(t, transport, http.MethodGet, url)
+
+	last := server.requests[len(server.requests)-1]
+	if got := last.Header.Get("If-None-Match"); got != "" {
+		t.Errorf("Expected an unconditional request after a write, got If-None-Match %q", got)
+	}
+}
+
+// TestCacheTransport_SeparatesTokens tests that different credentials never share entries
+func TestCacheTransport_SeparatesTokens(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	server := &cachingServer{etag: `"v1"`, body: `{"login":"alice"}`}
+	transport := newCacheTestTransport(t, server, &now)
+
+	get(t, transport, http.MethodGet, "https://api.github.com/user")
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	req.Header.Set("Authorization", "token other")
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if len(server.requests) != 2 {
+		t.Errorf("Expected 2 requests, got %d", len(server.requests))
+	}
+}
@@ -2,28 +2,71 @@ package github
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
 	graphql "github.com/cli/shurcooL-graphql"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"golang.org/x/sync/errgroup"
 )
 
 // Client wraps GitHub API clients
 type Client struct {
 	rest api.RESTClient
 	gql  api.GraphQLClient
+	host string
 }
 
-func NewClient() (*Client, error) {
-	restClient, err := api.DefaultRESTClient()
+// ClientOptions configures how the API clients are built
+type ClientOptions struct {
+	AuthToken   string        // empty resolves the token from the gh environment
+	Log         io.Writer     // receives verbose output such as the remaining API quota
+	Concurrency int           // maximum API requests in flight; zero uses DefaultConcurrency
+	CacheDir    string        // directory of the REST response cache; empty disables caching
+	Host        string        // API host such as a GitHub Enterprise Server; empty uses GH_HOST or github.com
+	Timeout     time.Duration // limit for each API request including its retries; zero means no limit
+}
+
+func NewClient(opts ClientOptions) (*Client, error) {
+	concurrency := opts.Concurrency
+	if concurrency == 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	// The limiter sits below the rate limit transport so backoff waits do not hold a slot
+	limited := &limitTransport{base: http.DefaultTransport, limiter: NewLimiter(concurrency)}
+	var transport http.RoundTripper = NewRateLimitTransport(limited, opts.Log)
+	if opts.CacheDir != "" {
+		// Fresh cache hits skip the network entirely and spend no quota
+		transport = NewCacheTransport(transport, opts.CacheDir, opts.Log)
+	}
+	host := opts.Host
+	if host == "" {
+		host, _ = auth.DefaultHost()
+	}
+	apiOpts := api.ClientOptions{
+		AuthToken: opts.AuthToken,
+		Host:      host,
+		Transport: transport,
+		Timeout:   opts.Timeout,
+	}
+
+	restClient, err := api.NewRESTClient(apiOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create REST client: %w", err)
 	}
 
-	gqlClient, err := api.DefaultGraphQLClient()
+	gqlClient, err := api.NewGraphQLClient(apiOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GraphQL client: %w", err)
 	}
@@ -31,51 +74,38 @@ func NewClient() (*Client, error) {
 	return &Client{
 		rest: *restClient,
 		gql:  *gqlClient,
+		host: host,
 	}, nil
 }
 
+// Host returns the API host the client talks to
+func (c *Client) Host() string {
+	return c.host
+}
+
 // GetCurrentUserLogin fetches current user's login
-func (c *Client) GetCurrentUserLogin() (string, error) {
+func (c *Client) GetCurrentUserLogin(ctx context.Context) (string, error) {
 	var user struct {
 		Login string `json:"login"`
 	}
-	if err := c.rest.Get("user", &user); err != nil {
+	if err := c.rest.DoWithContext(ctx, http.MethodGet, "user", nil, &user); err != nil {
 		return "", fmt.Errorf("failed to fetch current user: %w", err)
 	}
 	return user.Login, nil
 }
 
-// GetAssignedPRs fetches assigned pull requests using GraphQL
-func (c *Client) GetAssignedPRs(owner, repo, self string) ([]models.PullRequestInfo, error) {
+// GetAssignedPRs fetches the open pull requests assigned to self using GraphQL.
+// scope is a search qualifier such as "repo:owner/name" or "org:name"; empty searches every repository.
+// GitHub Enterprise Server serves the same search at /api/graphql, where the client built for its host sends it;
+// every qualifier used here, sort:created-desc included, is supported by the releases GitHub still supports.
+func (c *Client) GetAssignedPRs(ctx context.Context, scope, self string) ([]models.PullRequestInfo, error) {
+	return c.SearchPullRequests(ctx, assignedPRsQuery(scope, self), 0)
+}
+
+// SearchPullRequests runs a GitHub search query restricted to pull requests, following pagination until
+// limit results have been read (0 reads every page)
+func (c *Client) SearchPullRequests(ctx context.Context, query string, limit int) ([]models.PullRequestInfo, error) {
 	// NOTE: https://github.com/cli/go-gh/blob/a08820a13f257d6c5b4cb86d37db559ec6d14577/example_gh_test.go#L233
-	// query := `
-	// 	query ($query: String!, $first: Int = 100, $endCursor: String) {
-	// 		search(
-	// 			type: ISSUE,
-	// 			query: $query,
-	// 			first: $first,
-	// 			after: $endCursor
-	// 		) {
-	// 			nodes {
-	// 				... on PullRequest {
-	// 					number
-	// 					title
-	// 					state
-	// 					isDraft
-	// 					updatedAt
-	// 					createdAt
-	// 					author {
-	// 						login
-	// 					}
-	// 				}
-	// 			}
-	// 			pageInfo {
-	// 				hasNextPage
-	// 				endCursor
-	// 			}
-	// 		}
-	// 	}
-	// `
 	var q struct {
 		Search struct {
 			Nodes []struct {
@@ -89,6 +119,36 @@ func (c *Client) GetAssignedPRs(owner, repo, self string) ([]models.PullRequestI
 					Author    struct {
 						Login string
 					}
+					Repository struct {
+						Name  string
+						Owner struct {
+							Login string
+						}
+					}
+					ReviewDecision string
+					Mergeable      string
+					HeadRefName    string
+					Url            string
+					Additions      int
+					Deletions      int
+					ChangedFiles   int
+					ReviewRequests struct {
+						TotalCount int
+					}
+					Labels struct {
+						Nodes []struct {
+							Name string
+						}
+					} `graphql:"labels(first: 10)"`
+					Commits struct {
+						Nodes []struct {
+							Commit struct {
+								StatusCheckRollup struct {
+									State string
+								}
+							}
+						}
+					} `graphql:"commits(last: 1)"`
 				} `graphql:"... on PullRequest"`
 			}
 			PageInfo struct {
@@ -98,72 +158,202 @@ func (c *Client) GetAssignedPRs(owner, repo, self string) ([]models.PullRequestI
 		} `graphql:"search(type: ISSUE, query: $query, first: $first, after: $endCursor)"`
 	}
 
+	if !strings.Contains(query, "is:pr") {
+		query = "is:pr " + query
+	}
 	variables := map[string]interface{}{
-		"query":     graphql.String(fmt.Sprintf("repo:%s/%s is:pr state:open assignee:%s sort:created-desc", owner, repo, self)),
-		"first":     graphql.Int(100),
+		"query":     graphql.String(query),
+		"first":     graphql.Int(searchPageSize),
 		"endCursor": (*graphql.String)(nil),
 	}
 
-	err := c.gql.Query("", &q, variables)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
-	}
-
-	assigned := make([]models.PullRequestInfo, 0, len(q.Search.Nodes))
-	for _, node := range q.Search.Nodes {
-		pr := node.PullRequest
-		assigned = append(assigned, models.PullRequestInfo{
-			Number:    pr.Number,
-			Title:     pr.Title,
-			User:      pr.Author.Login,
-			State:     pr.State,
-			Draft:     pr.IsDraft,
-			UpdatedAt: pr.UpdatedAt,
-			CreatedAt: pr.CreatedAt,
-		})
+	var prs []models.PullRequestInfo
+	for {
+		if limit > 0 && limit-len(prs) < searchPageSize {
+			variables["first"] = graphql.Int(limit - len(prs))
+		}
+		if err := c.gql.QueryWithContext(ctx, "", &q, variables); err != nil {
+			return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
+		}
+
+		for _, node := range q.Search.Nodes {
+			pr := node.PullRequest
+			info := models.PullRequestInfo{
+				Owner:              pr.Repository.Owner.Login,
+				Repo:               pr.Repository.Name,
+				Number:             pr.Number,
+				Title:              pr.Title,
+				User:               pr.Author.Login,
+				State:              pr.State,
+				Draft:              pr.IsDraft,
+				UpdatedAt:          pr.UpdatedAt,
+				CreatedAt:          pr.CreatedAt,
+				ReviewDecision:     pr.ReviewDecision,
+				Mergeable:          pr.Mergeable,
+				RequestedReviewers: pr.ReviewRequests.TotalCount,
+				HeadBranch:         pr.HeadRefName,
+				URL:                pr.Url,
+				Additions:          pr.Additions,
+				Deletions:          pr.Deletions,
+				ChangedFiles:       pr.ChangedFiles,
+			}
+			for _, label := range pr.Labels.Nodes {
+				info.Labels = append(info.Labels, label.Name)
+			}
+			if len(pr.Commits.Nodes) > 0 {
+				info.ChecksState = pr.Commits.Nodes[0].Commit.StatusCheckRollup.State
+			}
+			prs = append(prs, info)
+		}
+
+		if !q.Search.PageInfo.HasNextPage || (limit > 0 && len(prs) >= limit) {
+			return prs, nil
+		}
+		variables["endCursor"] = graphql.NewString(graphql.String(q.Search.PageInfo.EndCursor))
 	}
-	return assigned, nil
 }
 
-// GetReviewersAndCommenters extracts users from PR reviews and comments
-func (c *Client) GetReviewersAndCommenters(owner, repo string, prNumber int, self string) ([]string, error) {
-	userSet := make(map[string]struct{}) // Use map as set
+// searchPageSize is the largest page the search API returns
+const searchPageSize = 100
 
-	// Get reviews
-	reviewPath := fmt.Sprintf("repos/%s/%s/pulls/%d/reviews", owner, repo, prNumber)
-	var reviews []models.Review
-	if err := c.rest.Get(reviewPath, &reviews); err != nil {
-		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+// assignedPRsQuery builds the search query, newest PRs first on every host
+func assignedPRsQuery(scope, self string) string {
+	return strings.TrimSpace(fmt.Sprintf("%s is:pr state:open assignee:%s sort:created-desc", scope, self))
+}
+
+// GetPullRequestPreview fetches the base branch, description, review requests, latest reviews and
+// checks of a PR for the preview of the PR pickers
+func (c *Client) GetPullRequestPreview(ctx context.Context, owner, repo string, prNumber int) (*models.PRPreview, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				BaseRefName    string
+				BodyText       string
+				ReviewRequests struct {
+					Nodes []struct {
+						RequestedReviewer struct {
+							User struct {
+								Login string
+							} `graphql:"... on User"`
+							Team struct {
+								CombinedSlug string
+							} `graphql:"... on Team"`
+						}
+					}
+				} `graphql:"reviewRequests(first: 20)"`
+				LatestReviews struct {
+					Nodes []struct {
+						Author struct {
+							Login string
+						}
+						State       string
+						SubmittedAt string
+						Commit      struct {
+							Oid string
+						}
+					}
+				} `graphql:"latestReviews(first: 20)"`
+				Commits struct {
+					Nodes []struct {
+						Commit struct {
+							StatusCheckRollup struct {
+								Contexts struct {
+									Nodes []struct {
+										CheckRun struct {
+											Status     string
+											Conclusion string
+										} `graphql:"... on CheckRun"`
+										StatusContext struct {
+											State string
+										} `graphql:"... on StatusContext"`
+									}
+								} `graphql:"contexts(first: 100)"`
+							}
+						}
+					}
+				} `graphql:"commits(last: 1)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":  graphql.String(owner),
+		"name":   graphql.String(repo),
+		"number": graphql.Int(prNumber),
+	}
+	if err := c.gql.QueryWithContext(ctx, "", &q, variables); err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request preview: %w", err)
 	}
 
-	for _, review := range reviews {
-		login := review.User.Login
-		typeStr := review.User.Type
-		if c.isValidUser(login, typeStr, self) {
-			userSet[login] = struct{}{}
+	pr := q.Repository.PullRequest
+	preview := &models.PRPreview{BaseBranch: pr.BaseRefName, Body: pr.BodyText}
+	for _, request := range pr.ReviewRequests.Nodes {
+		if login := request.RequestedReviewer.User.Login; login != "" {
+			preview.ReviewRequests = append(preview.ReviewRequests, login)
+		} else if team := request.RequestedReviewer.Team.CombinedSlug; team != "" {
+			preview.ReviewRequests = append(preview.ReviewRequests, "@"+team)
+		}
+	}
+	for _, review := range pr.LatestReviews.Nodes {
+		preview.LatestReviews = append(preview.LatestReviews, models.Review{
+			User:        models.User{Login: review.Author.Login},
+			State:       review.State,
+			CommitID:    review.Commit.Oid,
+			SubmittedAt: review.SubmittedAt,
+		})
+	}
+	if len(pr.Commits.Nodes) > 0 {
+		if contexts := pr.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts.Nodes; len(contexts) > 0 {
+			preview.Checks = &models.CheckSummary{}
+			for _, check := range contexts {
+				preview.Checks.Add(check.CheckRun.Status, check.CheckRun.Conclusion, check.StatusContext.State)
+			}
 		}
 	}
+	return preview, nil
+}
 
-	// Get issue comments
-	commentPath := fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, prNumber)
+// GetReviewersAndCommenters extracts users from PR reviews and comments, sorted by login
+func (c *Client) GetReviewersAndCommenters(ctx context.Context, owner, repo string, prNumber int, self string) ([]string, error) {
+	var reviews []models.Review
 	var comments []models.Comment
-	if err := c.rest.Get(commentPath, &comments); err != nil {
-		return nil, fmt.Errorf("failed to fetch comments: %w", err)
+
+	// Fetch every source at once; the first failure cancels the others
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		reviews, err = c.GetReviews(gctx, owner, repo, prNumber)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		comments, err = listAll[models.Comment](gctx, &c.rest, fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, prNumber))
+		if err != nil {
+			return fmt.Errorf("failed to fetch comments: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
 
+	userSet := make(map[string]struct{}) // Use map as set
+	for _, review := range reviews {
+		if c.isValidUser(review.User.Login, review.User.Type, self) {
+			userSet[review.User.Login] = struct{}{}
+		}
+	}
 	for _, comment := range comments {
-		login := comment.User.Login
-		typeStr := comment.User.Type
-		if c.isValidUser(login, typeStr, self) {
-			userSet[login] = struct{}{}
+		if c.isValidUser(comment.User.Login, comment.User.Type, self) {
+			userSet[comment.User.Login] = struct{}{}
 		}
 	}
 
-	// Convert map to slice
+	// Convert map to slice in a stable order
 	users := make([]string, 0, len(userSet))
 	for u := range userSet {
 		users = append(users, u)
 	}
+	sort.Strings(users)
 	return users, nil
 }
 
@@ -176,7 +366,7 @@ func (c *Client) isValidUser(login, userType, self string) bool {
 }
 
 // ReassignReviewers sends review request to specified reviewers
-func (c *Client) ReassignReviewers(owner, repo string, prNumber int, reviewers []string) error {
+func (c *Client) ReassignReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers []string) error {
 	path := fmt.Sprintf("repos/%s/%s/pulls/%d/requested_reviewers", owner, repo, prNumber)
 
 	jsonBody, err := json.Marshal(map[string]interface{}{
@@ -187,9 +377,253 @@ func (c *Client) ReassignReviewers(owner, repo string, prNumber int, reviewers [
 	}
 
 	var response interface{}
-	err = c.rest.Post(path, bytes.NewReader(jsonBody), &response)
+	err = c.rest.DoWithContext(ctx, http.MethodPost, path, bytes.NewReader(jsonBody), &response)
 	if err != nil {
 		return fmt.Errorf("failed to assign reviewers: %w", err)
 	}
 	return nil
 }
+
+// GetPullRequestHeadSHA fetches the current head commit of the PR
+func (c *Client) GetPullRequestHeadSHA(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d", owner, repo, prNumber)
+	var pr struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := c.rest.DoWithContext(ctx, http.MethodGet, path, nil, &pr); err != nil {
+		return "", fmt.Errorf("failed to fetch pull request: %w", err)
+	}
+	return pr.Head.SHA, nil
+}
+
+// GetReviews fetches the reviews of the PR in chronological order
+func (c *Client) GetReviews(ctx context.Context, owner, repo string, prNumber int) ([]models.Review, error) {
+	reviews, err := listAll[models.Review](ctx, &c.rest, fmt.Sprintf("repos/%s/%s/pulls/%d/reviews", owner, repo, prNumber))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// restPageSize is the largest page the REST list endpoints return
+const restPageSize = 100
+
+// nextPageRE finds the URL of the next page in a Link header
+var nextPageRE = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// listAll reads every page of a REST list endpoint, following the Link header
+func listAll[T any](ctx context.Context, rest *api.RESTClient, path string) ([]T, error) {
+	var items []T
+	next := fmt.Sprintf("%s?per_page=%d", path, restPageSize)
+	for next != "" {
+		resp, err := rest.RequestWithContext(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []T
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		items = append(items, page...)
+
+		next = ""
+		if match := nextPageRE.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			next = match[1]
+		}
+	}
+	return items, nil
+}
+
+// CompareCommits summarizes the changes between base and head using the compare API
+func (c *Client) CompareCommits(ctx context.Context, owner, repo, base, head string) (*models.CompareSummary, error) {
+	path := fmt.Sprintf("repos/%s/%s/compare/%s...%s", owner, repo, base, head)
+	var comparison struct {
+		TotalCommits int `json:"total_commits"`
+		Files        []struct {
+			Additions int `json:"additions"`
+			Deletions int `json:"deletions"`
+		} `json:"files"`
+	}
+	if err := c.rest.DoWithContext(ctx, http.MethodGet, path, nil, &comparison); err != nil {
+		var httpErr *api.HTTPError
+		if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusNotFound || httpErr.StatusCode == http.StatusUnprocessableEntity) {
+			return nil, fmt.Errorf("failed to compare commits: %w: %w", ErrCommitNotFound, err)
+		}
+		return nil, fmt.Errorf("failed to compare commits: %w", err)
+	}
+
+	summary := &models.CompareSummary{
+		BaseCommit:   base,
+		HeadCommit:   head,
+		Commits:      comparison.TotalCommits,
+		FilesChanged: len(comparison.Files),
+	}
+	for _, file := range comparison.Files {
+		summary.Additions += file.Additions
+		summary.Deletions += file.Deletions
+	}
+	return summary, nil
+}
+
+// ErrCommitNotFound is returned when a compared commit no longer exists, e.g. after a force push
+var ErrCommitNotFound = errors.New("commit not found")
+
+// GetRequestedReviewers fetches users whose review is currently requested
+func (c *Client) GetRequestedReviewers(ctx context.Context, owner, repo string, prNumber int) ([]string, error) {
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d/requested_reviewers", owner, repo, prNumber)
+	var requested struct {
+		Users []models.User `json:"users"`
+	}
+	if err := c.rest.DoWithContext(ctx, http.MethodGet, path, nil, &requested); err != nil {
+		return nil, fmt.Errorf("failed to fetch requested reviewers: %w", err)
+	}
+
+	logins := make([]string, 0, len(requested.Users))
+	for _, user := range requested.Users {
+		logins = append(logins, user.Login)
+	}
+	return logins, nil
+}
+
+// RemoveReviewRequests withdraws pending review requests from the specified reviewers
+func (c *Client) RemoveReviewRequests(ctx context.Context, owner, repo string, prNumber int, reviewers []string) error {
+	path := fmt.Sprintf("repos/%s/%s/pulls/%d/requested_reviewers", owner, repo, prNumber)
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"reviewers": reviewers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	var response interface{}
+	err = c.rest.DoWithContext(ctx, http.MethodDelete, path, bytes.NewReader(jsonBody), &response)
+	if err != nil {
+		return fmt.Errorf("failed to remove review requests: %w", err)
+	}
+	return nil
+}
+
+// CreateIssueComment posts a comment on the PR conversation
+func (c *Client) CreateIssueComment(ctx context.Context, owner, repo string, prNumber int, body string) error {
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/comments", owner, repo, prNumber)
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"body": body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	var response interface{}
+	err = c.rest.DoWithContext(ctx, http.MethodPost, path, bytes.NewReader(jsonBody), &response)
+	if err != nil {
+		return fmt.Errorf("failed to post comment: %w", err)
+	}
+	return nil
+}
+
+// GetReviewRequestHistory fetches the latest review requests of the PR from its timeline, oldest first
+func (c *Client) GetReviewRequestHistory(ctx context.Context, owner, repo string, prNumber int) ([]models.ReviewRequest, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				TimelineItems struct {
+					Nodes []struct {
+						ReviewRequestedEvent struct {
+							CreatedAt         time.Time
+							RequestedReviewer struct {
+								User struct {
+									Login string
+								} `graphql:"... on User"`
+							}
+						} `graphql:"... on ReviewRequestedEvent"`
+					}
+				} `graphql:"timelineItems(last: 100, itemTypes: [REVIEW_REQUESTED_EVENT])"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"owner":  graphql.String(owner),
+		"name":   graphql.String(repo),
+		"number": graphql.Int(prNumber),
+	}
+	if err := c.gql.QueryWithContext(ctx, "", &q, variables); err != nil {
+		return nil, fmt.Errorf("failed to fetch review request history: %w", err)
+	}
+
+	var requests []models.ReviewRequest
+	for _, node := range q.Repository.PullRequest.TimelineItems.Nodes {
+		event := node.ReviewRequestedEvent
+		// Team requests have no user login
+		if event.RequestedReviewer.User.Login == "" {
+			continue
+		}
+		requests = append(requests, models.ReviewRequest{
+			Reviewer:    event.RequestedReviewer.User.Login,
+			RequestedAt: event.CreatedAt,
+		})
+	}
+	return requests, nil
+}
+
+// GetUserStatuses fetches the profile status of each user in one GraphQL request; users without a status are omitted.
+// A user that cannot be resolved, e.g. after a rename, fails only their own field, so the others are still returned.
+func (c *Client) GetUserStatuses(ctx context.Context, logins []string) (map[string]models.UserStatus, error) {
+	statuses := make(map[string]models.UserStatus)
+	if len(logins) == 0 {
+		return statuses, nil
+	}
+
+	// One aliased field per user, e.g. u0: user(login: $l0) { ... }
+	var params, fields []string
+	variables := make(map[string]interface{}, len(logins))
+	for i, login := range logins {
+		params = append(params, fmt.Sprintf("$l%d: String!", i))
+		fields = append(fields, fmt.Sprintf("u%d: user(login: $l%d) { status { message indicatesLimitedAvailability } }", i, i))
+		variables[fmt.Sprintf("l%d", i)] = login
+	}
+	query := fmt.Sprintf("query UserStatuses(%s) { %s }", strings.Join(params, ", "), strings.Join(fields, " "))
+
+	var response map[string]*struct {
+		Status *struct {
+			Message                      string
+			IndicatesLimitedAvailability bool
+		}
+	}
+	if err := c.gql.DoWithContext(ctx, query, variables, &response); err != nil && !fieldErrorsOnly(err) {
+		return nil, fmt.Errorf("failed to fetch user statuses: %w", err)
+	}
+
+	for i, login := range logins {
+		user := response[fmt.Sprintf("u%d", i)]
+		if user == nil || user.Status == nil {
+			continue
+		}
+		statuses[login] = models.UserStatus{
+			Busy:    user.Status.IndicatesLimitedAvailability,
+			Message: user.Status.Message,
+		}
+	}
+	return statuses, nil
+}
+
+// fieldErrorsOnly reports whether err only lists errors of single fields, e.g. NOT_FOUND for one aliased user.
+// GraphQL still returns the data of the other fields alongside them.
+func fieldErrorsOnly(err error) bool {
+	var gqlErr *api.GraphQLError
+	if !errors.As(err, &gqlErr) {
+		return false
+	}
+	for _, item := range gqlErr.Errors {
+		if len(item.Path) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,10 +1,17 @@
 package github
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 )
 
 func TestClient_isValidUser(t *testing.T) {
@@ -134,3 +141,255 @@ func TestClient_GetReviewersAndCommenters(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetReviewersAndCommentersReadsEveryPage(t *testing.T) {
+	var urls []string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		urls = append(urls, req.URL.String())
+		header := http.Header{"Content-Type": []string{"application/json"}}
+		var body string
+		switch {
+		case strings.Contains(req.URL.Path, "/reviews") && req.URL.Query().Get("page") == "":
+			header.Set("Link", `<https://api.github.com/repositories/1/pulls/7/reviews?per_page=100&page=2>; rel="next", `+
+				`<https://api.github.com/repositories/1/pulls/7/reviews?per_page=100&page=2>; rel="last"`)
+			body = `[{"user":{"login":"alice","type":"User"},"commit_id":"a"}]`
+		case strings.Contains(req.URL.Path, "/reviews"):
+			body = `[{"user":{"login":"bob","type":"User"},"commit_id":"b"}]`
+		default:
+			body = `[{"user":{"login":"carol","type":"User"}}]`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(body)), Request: req}, nil
+	})
+	rest, err := api.NewRESTClient(api.ClientOptions{AuthToken: "token", Host: "github.com", Transport: transport})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client := &Client{rest: *rest, host: "github.com"}
+
+	users, err := client.GetReviewersAndCommenters(context.Background(), "acme", "api", 7, "me")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(users, ",") != "alice,bob,carol" {
+		t.Errorf("Expected the users of every page, got %v", users)
+	}
+	if len(urls) != 3 {
+		t.Fatalf("Expected 3 requests, got %v", urls)
+	}
+	for _, url := range urls {
+		if !strings.Contains(url, "per_page=100") {
+			t.Errorf("Expected pages of 100, got %s", url)
+		}
+	}
+}
+
+func TestClient_SearchPullRequests(t *testing.T) {
+	var requests []map[string]interface{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		requests = append(requests, body.Variables)
+
+		page := `{"data":{"search":{"nodes":[
+			{"number":1,"title":"First","repository":{"name":"api","owner":{"login":"acme"}},"author":{"login":"alice"},
+			 "reviewDecision":"CHANGES_REQUESTED","mergeable":"CONFLICTING","headRefName":"fix/search",
+			 "url":"https://github.com/acme/api/pull/1","additions":12,"deletions":3,"changedFiles":2,
+			 "reviewRequests":{"totalCount":2},"labels":{"nodes":[{"name":"bug"}]},
+			 "commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"FAILURE"}}}]}},
+			{"number":2,"title":"Second","repository":{"name":"web","owner":{"login":"acme"}},"author":{"login":"bob"},
+			 "reviewDecision":null,"commits":{"nodes":[{"commit":{"statusCheckRollup":null}}]}}
+		],"pageInfo":{"hasNextPage":true,"endCursor":"page2"}}}}`
+		if body.Variables["endCursor"] == "page2" {
+			page = `{"data":{"search":{"nodes":[
+				{"number":3,"title":"Third","repository":{"name":"api","owner":{"login":"acme"}},"author":{"login":"carol"}}
+			],"pageInfo":{"hasNextPage":false,"endCursor":"page3"}}}}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(page)),
+			Request:    req,
+		}, nil
+	})
+	gql, err := api.NewGraphQLClient(api.ClientOptions{AuthToken: "token", Host: "github.com", Transport: transport})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client := &Client{gql: *gql, host: "github.com"}
+
+	prs, err := client.SearchPullRequests(context.Background(), "org:acme review-requested:me", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prs) != 3 || len(requests) != 2 {
+		t.Fatalf("Expected 3 PRs from 2 pages, got %d PRs from %d requests", len(prs), len(requests))
+	}
+	if prs[1].Owner != "acme" || prs[1].Repo != "web" || prs[1].User != "bob" {
+		t.Errorf("Unexpected PR: %+v", prs[1])
+	}
+	first := prs[0]
+	if first.ReviewDecision != "CHANGES_REQUESTED" || first.ChecksState != "FAILURE" || first.Mergeable != "CONFLICTING" ||
+		first.RequestedReviewers != 2 || len(first.Labels) != 1 || first.Labels[0] != "bug" || first.HeadBranch != "fix/search" {
+		t.Errorf("Unexpected PR details: %+v", first)
+	}
+	if first.URL != "https://github.com/acme/api/pull/1" || first.Additions != 12 || first.Deletions != 3 || first.ChangedFiles != 2 {
+		t.Errorf("Unexpected PR URL or diff stats: %+v", first)
+	}
+	if prs[1].ChecksState != "" {
+		t.Errorf("Expected no checks on a PR without a status check rollup, got %q", prs[1].ChecksState)
+	}
+	if requests[0]["query"] != "is:pr org:acme review-requested:me" {
+		t.Errorf("Expected the query to be restricted to PRs, got %v", requests[0]["query"])
+	}
+
+	// The limit stops paging and shrinks the page
+	requests = nil
+	prs, err = client.SearchPullRequests(context.Background(), "is:pr org:acme", 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prs) != 2 || len(requests) != 1 || requests[0]["first"] != float64(2) {
+		t.Errorf("Expected one page of 2 PRs, got %d PRs from %v", len(prs), requests)
+	}
+}
+
+// TestClient_GetUserStatusesPartial tests that one unknown login does not hide the statuses of the others
+func TestClient_GetUserStatusesPartial(t *testing.T) {
+	tests := []struct {
+		name      string
+		response  string
+		expectErr bool
+	}{
+		{
+			name: "unknown user",
+			response: `{"data":{"u0":{"status":{"message":"On vacation","indicatesLimitedAvailability":true}},"u1":null},
+				"errors":[{"type":"NOT_FOUND","path":["u1"],"message":"Could not resolve to a User with the login of 'renamed'."}]}`,
+		},
+		{
+			name:      "request error",
+			response:  `{"data":null,"errors":[{"message":"Parse error on \"}\""}]}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(tt.response)),
+					Request:    req,
+				}, nil
+			})
+			gql, err := api.NewGraphQLClient(api.ClientOptions{AuthToken: "token", Host: "github.com", Transport: transport})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			client := &Client{gql: *gql, host: "github.com"}
+
+			statuses, err := client.GetUserStatuses(context.Background(), []string{"alice", "renamed"})
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(statuses) != 1 || !statuses["alice"].Busy || statuses["alice"].Message != "On vacation" {
+				t.Errorf("Expected alice to be busy, got %+v", statuses)
+			}
+		})
+	}
+}
+
+func TestClient_GetPullRequestPreview(t *testing.T) {
+	var variables map[string]interface{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		variables = body.Variables
+
+		response := `{"data":{"repository":{"pullRequest":{"baseRefName":"main","bodyText":"Fixes search",
+			"reviewRequests":{"nodes":[{"requestedReviewer":{"login":"dave"}},{"requestedReviewer":{"combinedSlug":"acme/backend"}}]},
+			"latestReviews":{"nodes":[{"author":{"login":"erin"},"state":"CHANGES_REQUESTED","commit":{"oid":"abc"}}]},
+			"commits":{"nodes":[{"commit":{"statusCheckRollup":{"contexts":{"nodes":[
+			  {"status":"COMPLETED","conclusion":"SUCCESS"},{"status":"COMPLETED","conclusion":"FAILURE"},
+			  {"status":"IN_PROGRESS","conclusion":null},{"status":"COMPLETED","conclusion":"SKIPPED"},{"state":"PENDING"}]}}}}]}}}}}`
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(response)),
+			Request:    req,
+		}, nil
+	})
+	gql, err := api.NewGraphQLClient(api.ClientOptions{AuthToken: "token", Host: "github.com", Transport: transport})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client := &Client{gql: *gql, host: "github.com"}
+
+	preview, err := client.GetPullRequestPreview(context.Background(), "acme", "api", 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if variables["owner"] != "acme" || variables["name"] != "api" || variables["number"] != float64(1) {
+		t.Errorf("Unexpected variables: %v", variables)
+	}
+	if preview.BaseBranch != "main" || preview.Body != "Fixes search" ||
+		strings.Join(preview.ReviewRequests, ",") != "dave,@acme/backend" ||
+		len(preview.LatestReviews) != 1 || preview.LatestReviews[0].User.Login != "erin" || preview.LatestReviews[0].CommitID != "abc" {
+		t.Errorf("Unexpected PR preview: %+v", preview)
+	}
+	if preview.Checks == nil || *preview.Checks != (models.CheckSummary{Passed: 1, Failed: 1, Pending: 2, Skipped: 1}) {
+		t.Errorf("Unexpected check summary: %+v", preview.Checks)
+	}
+}
+
+func TestClient_CompareCommitsNotFound(t *testing.T) {
+	tests := []struct {
+		status   int
+		notFound bool
+	}{
+		{status: http.StatusNotFound, notFound: true},
+		{status: http.StatusUnprocessableEntity, notFound: true},
+		{status: http.StatusInternalServerError, notFound: false},
+		{status: http.StatusUnauthorized, notFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: tt.status,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"message":"failed"}`)),
+					Request:    req,
+				}, nil
+			})
+			rest, err := api.NewRESTClient(api.ClientOptions{AuthToken: "token", Host: "github.com", Transport: transport})
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			client := &Client{rest: *rest, host: "github.com"}
+
+			_, err = client.CompareCommits(context.Background(), "owner", "repo", "old", "head")
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if errors.Is(err, ErrCommitNotFound) != tt.notFound {
+				t.Errorf("Expected errors.Is(err, ErrCommitNotFound) = %v, got %v", tt.notFound, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// DefaultHost is the host of github.com
+const DefaultHost = "github.com"
+
+// ParsePullRequestURL extracts the host and PR from a URL such as https://ghe.example.com/owner/repo/pull/123
+func ParsePullRequestURL(raw string) (string, models.PRRef, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", models.PRRef{}, fmt.Errorf("invalid pull request URL: %s", raw)
+	}
+
+	// Trailing segments such as /files or /commits are ignored
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || parts[2] != "pull" {
+		return "", models.PRRef{}, fmt.Errorf("not a pull request URL: %s", raw)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return "", models.PRRef{}, fmt.Errorf("invalid PR number in URL: %s", raw)
+	}
+
+	host := auth.NormalizeHostname(u.Hostname())
+	return host, models.PRRef{Owner: parts[0], Repo: parts[1], Number: number}, nil
+}
+
+// IsEnterprise reports whether host is a GitHub Enterprise Server instance
+func IsEnterprise(host string) bool {
+	return host != "" && auth.IsEnterprise(auth.NormalizeHostname(host))
+}
@@ -0,0 +1,111 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+func TestParsePullRequestURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectHost  string
+		expectRef   models.PRRef
+		expectError bool
+	}{
+		{
+			name:       "github.com",
+			input:      "https://github.com/owner/repo/pull/123",
+			expectHost: "github.com",
+			expectRef:  models.PRRef{Owner: "owner", Repo: "repo", Number: 123},
+		},
+		{
+			name:       "enterprise server with trailing path",
+			input:      "https://GHE.example.com/team/service/pull/7/files",
+			expectHost: "ghe.example.com",
+			expectRef:  models.PRRef{Owner: "team", Repo: "service", Number: 7},
+		},
+		{name: "issue URL", input: "https://github.com/owner/repo/issues/1", expectError: true},
+		{name: "missing number", input: "https://github.com/owner/repo/pull", expectError: true},
+		{name: "invalid number", input: "https://github.com/owner/repo/pull/abc", expectError: true},
+		{name: "not a URL", input: "owner/repo#1", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			host, ref, err := ParsePullRequestURL(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if host != tt.expectHost {
+				t.Errorf("Expected host %q, got %q", tt.expectHost, host)
+			}
+			if ref != tt.expectRef {
+				t.Errorf("Expected %+v, got %+v", tt.expectRef, ref)
+			}
+		})
+	}
+}
+
+func TestAssignedPRsQuery(t *testing.T) {
+	if got := assignedPRsQuery("repo:o/r", "me"); got != "repo:o/r is:pr state:open assignee:me sort:created-desc" {
+		t.Errorf("Unexpected query: %q", got)
+	}
+	if got := assignedPRsQuery("", "me"); got != "is:pr state:open assignee:me sort:created-desc" {
+		t.Errorf("Unexpected query across repositories: %q", got)
+	}
+}
+
+func TestClient_GetAssignedPRsOnEnterpriseServer(t *testing.T) {
+	var endpoint, query string
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		endpoint, query = req.URL.String(), body.Variables["query"].(string)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body: io.NopCloser(strings.NewReader(`{"data":{"search":{"nodes":[
+				{"number":7,"title":"Fix","repository":{"name":"service","owner":{"login":"team"}},"author":{"login":"alice"}}
+			],"pageInfo":{"hasNextPage":false}}}}`)),
+			Request: req,
+		}, nil
+	})
+	gql, err := api.NewGraphQLClient(api.ClientOptions{AuthToken: "token", Host: "ghe.example.com", Transport: transport})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	client := &Client{gql: *gql, host: "ghe.example.com"}
+
+	prs, err := client.GetAssignedPRs(context.Background(), "repo:team/service", "me")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if endpoint != "https://ghe.example.com/api/graphql" {
+		t.Errorf("Expected the search to go to the enterprise GraphQL endpoint, got %s", endpoint)
+	}
+	if query != "repo:team/service is:pr state:open assignee:me sort:created-desc" {
+		t.Errorf("Expected the same search as on github.com, got %q", query)
+	}
+	if len(prs) != 1 || prs[0].Owner != "team" || prs[0].Number != 7 {
+		t.Errorf("Unexpected PRs: %+v", prs)
+	}
+}
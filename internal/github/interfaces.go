@@ -1,15 +1,28 @@
 package github
 
 import (
+	"context"
+
 	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 )
 
 // GitHubClient defines the interface for GitHub operations
 type GitHubClient interface {
-	GetCurrentUserLogin() (string, error)
-	GetAssignedPRs(owner, repo, self string) ([]models.PullRequestInfo, error)
-	GetReviewersAndCommenters(owner, repo string, prNumber int, self string) ([]string, error)
-	ReassignReviewers(owner, repo string, prNumber int, reviewers []string) error
+	Host() string
+	GetCurrentUserLogin(ctx context.Context) (string, error)
+	GetAssignedPRs(ctx context.Context, scope, self string) ([]models.PullRequestInfo, error)
+	SearchPullRequests(ctx context.Context, query string, limit int) ([]models.PullRequestInfo, error)
+	GetPullRequestPreview(ctx context.Context, owner, repo string, prNumber int) (*models.PRPreview, error)
+	GetReviewersAndCommenters(ctx context.Context, owner, repo string, prNumber int, self string) ([]string, error)
+	ReassignReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers []string) error
+	GetPullRequestHeadSHA(ctx context.Context, owner, repo string, prNumber int) (string, error)
+	GetReviews(ctx context.Context, owner, repo string, prNumber int) ([]models.Review, error)
+	CompareCommits(ctx context.Context, owner, repo, base, head string) (*models.CompareSummary, error)
+	GetRequestedReviewers(ctx context.Context, owner, repo string, prNumber int) ([]string, error)
+	RemoveReviewRequests(ctx context.Context, owner, repo string, prNumber int, reviewers []string) error
+	CreateIssueComment(ctx context.Context, owner, repo string, prNumber int, body string) error
+	GetReviewRequestHistory(ctx context.Context, owner, repo string, prNumber int) ([]models.ReviewRequest, error)
+	GetUserStatuses(ctx context.Context, logins []string) (map[string]models.UserStatus, error)
 }
 
 // RepositoryInfo defines repository information interface
@@ -18,5 +31,19 @@ type RepositoryInfo interface {
 	GetName() string
 }
 
+// Repository is a RepositoryInfo with a fixed owner and name
+type Repository struct {
+	Owner string
+	Name  string
+}
+
+func (r *Repository) GetOwner() string {
+	return r.Owner
+}
+
+func (r *Repository) GetName() string {
+	return r.Name
+}
+
 // Ensure Client implements GitHubClient interface
 var _ GitHubClient = (*Client)(nil)
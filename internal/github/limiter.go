@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DefaultConcurrency is the number of API requests allowed in flight when none is configured
+const DefaultConcurrency = 4
+
+// Limiter caps the number of API requests in flight, shared by every operation of a client
+type Limiter struct {
+	slots chan struct{}
+}
+
+// NewLimiter allows n concurrent requests; n <= 0 means no limit
+func NewLimiter(n int) *Limiter {
+	if n <= 0 {
+		return &Limiter{}
+	}
+	return &Limiter{slots: make(chan struct{}, n)}
+}
+
+// Acquire blocks until a slot is free or ctx is cancelled
+func (l *Limiter) Acquire(ctx context.Context) error {
+	if l.slots == nil {
+		return ctx.Err()
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (l *Limiter) Release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// limitTransport holds a limiter slot from sending a request until its body is closed
+type limitTransport struct {
+	base    http.RoundTripper
+	limiter *Limiter
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Acquire(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.limiter.Release()
+		return nil, err
+	}
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: t.limiter.Release}
+	return resp, nil
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLimitTransport_CapsRequestsInFlight tests that concurrent requests never exceed the limit
+func TestLimitTransport_CapsRequestsInFlight(t *testing.T) {
+	var inFlight, peak int32
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	})
+	transport := &limitTransport{base: base, limiter: NewLimiter(2)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", peak)
+	}
+}
+
+// TestLimiter_AcquireCancelled tests that waiting for a slot stops when the context is cancelled
+func TestLimiter_AcquireCancelled(t *testing.T) {
+	limiter := NewLimiter(1)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+
+	limiter.Release()
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Errorf("Expected slot after release, got %v", err)
+	}
+}
+
+// TestLimiter_Unlimited tests that a non-positive limit never blocks
+func TestLimiter_Unlimited(t *testing.T) {
+	limiter := NewLimiter(0)
+	for i := 0; i < 100; i++ {
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	limiter.Release()
+}
@@ -1,8 +1,10 @@
 package github
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 )
@@ -10,43 +12,110 @@ import (
 // MockClient implements GitHubClient for testing
 type MockClient struct {
 	// Control test behavior
+	APIHost             string
 	CurrentUser         string
 	CurrentUserError    error
 	AssignedPRs         []models.PullRequestInfo
+	SearchResults       map[string][]models.PullRequestInfo // keyed by query
+	SearchError         error
+	Previews            map[string]*models.PRPreview // keyed by "owner/repo#number"
+	PreviewError        error
 	AssignedPRsError    error
 	ReviewersCommenters []string
 	ReviewersError      error
 	ReassignError       error
+	HeadSHA             string
+	HeadSHAError        error
+	Reviews             []models.Review
+	ReviewsError        error
+	CompareSummaries    map[string]*models.CompareSummary // keyed by base commit
+	CompareError        error
+	RequestedReviewers  []string
+	RequestedError      error
+	RemoveError         error
+	CommentError        error
+	ReviewRequests      []models.ReviewRequest
+	ReviewRequestsError error
+	UserStatuses        map[string]models.UserStatus
+	UserStatusesError   error
 
 	// Track method calls
 	GetCurrentUserLoginCalled       bool
 	GetAssignedPRsCalled            bool
+	SearchQueries                   []string
+	PreviewCalls                    int
 	GetReviewersAndCommentersCalled bool
 	ReassignReviewersCalled         bool
+	GetPullRequestHeadSHACalled     bool
+	GetReviewsCalled                bool
+	CompareCommitsCalls             int
+	GetRequestedReviewersCalled     bool
+	RemoveReviewRequestsCalled      bool
+	CreateIssueCommentCalled        bool
+	GetReviewRequestHistoryCalled   bool
+	GetUserStatusesCalled           bool
 
 	// Store call arguments for verification
+	LastScope     string
 	LastOwner     string
 	LastRepo      string
 	LastPRNumber  int
 	LastReviewers []string
+	LastRemoved   []string
+	LastComment   string
+
+	// The service calls the client from several goroutines
+	mu sync.Mutex
+}
+
+// Host returns APIHost
+func (m *MockClient) Host() string {
+	return m.APIHost
 }
 
 // GetCurrentUserLogin mocks the GitHub API call
-func (m *MockClient) GetCurrentUserLogin() (string, error) {
+func (m *MockClient) GetCurrentUserLogin(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.GetCurrentUserLoginCalled = true
 	return m.CurrentUser, m.CurrentUserError
 }
 
 // GetAssignedPRs mocks the GraphQL API call
-func (m *MockClient) GetAssignedPRs(owner, repo, self string) ([]models.PullRequestInfo, error) {
+func (m *MockClient) GetAssignedPRs(ctx context.Context, scope, self string) ([]models.PullRequestInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.GetAssignedPRsCalled = true
-	m.LastOwner = owner
-	m.LastRepo = repo
+	m.LastScope = scope
 	return m.AssignedPRs, m.AssignedPRsError
 }
 
+// SearchPullRequests mocks the GraphQL search, returning the results registered for query
+func (m *MockClient) SearchPullRequests(ctx context.Context, query string, limit int) ([]models.PullRequestInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.SearchQueries = append(m.SearchQueries, query)
+	return m.SearchResults[query], m.SearchError
+}
+
+// GetPullRequestPreview mocks the pull request preview query
+func (m *MockClient) GetPullRequestPreview(ctx context.Context, owner, repo string, prNumber int) (*models.PRPreview, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.PreviewCalls++
+	if m.PreviewError != nil {
+		return nil, m.PreviewError
+	}
+	if preview, ok := m.Previews[fmt.Sprintf("%s/%s#%d", owner, repo, prNumber)]; ok {
+		return preview, nil
+	}
+	return &models.PRPreview{}, nil
+}
+
 // GetReviewersAndCommenters mocks the REST API calls
-func (m *MockClient) GetReviewersAndCommenters(owner, repo string, prNumber int, self string) ([]string, error) {
+func (m *MockClient) GetReviewersAndCommenters(ctx context.Context, owner, repo string, prNumber int, self string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.GetReviewersAndCommentersCalled = true
 	m.LastOwner = owner
 	m.LastRepo = repo
@@ -55,7 +124,9 @@ func (m *MockClient) GetReviewersAndCommenters(owner, repo string, prNumber int,
 }
 
 // ReassignReviewers mocks the review request API call
-func (m *MockClient) ReassignReviewers(owner, repo string, prNumber int, reviewers []string) error {
+func (m *MockClient) ReassignReviewers(ctx context.Context, owner, repo string, prNumber int, reviewers []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.ReassignReviewersCalled = true
 	m.LastOwner = owner
 	m.LastRepo = repo
@@ -64,16 +135,134 @@ func (m *MockClient) ReassignReviewers(owner, repo string, prNumber int, reviewe
 	return m.ReassignError
 }
 
+// GetPullRequestHeadSHA mocks the pull request API call
+func (m *MockClient) GetPullRequestHeadSHA(ctx context.Context, owner, repo string, prNumber int) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.GetPullRequestHeadSHACalled = true
+	m.LastOwner = owner
+	m.LastRepo = repo
+	m.LastPRNumber = prNumber
+	return m.HeadSHA, m.HeadSHAError
+}
+
+// GetReviews mocks the reviews API call
+func (m *MockClient) GetReviews(ctx context.Context, owner, repo string, prNumber int) ([]models.Review, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.GetReviewsCalled = true
+	m.LastOwner = owner
+	m.LastRepo = repo
+	m.LastPRNumber = prNumber
+	return m.Reviews, m.ReviewsError
+}
+
+// CompareCommits mocks the compare API call
+func (m *MockClient) CompareCommits(ctx context.Context, owner, repo, base, head string) (*models.CompareSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CompareCommitsCalls++
+	m.LastOwner = owner
+	m.LastRepo = repo
+	if m.CompareError != nil {
+		return nil, m.CompareError
+	}
+	summary, ok := m.CompareSummaries[base]
+	if !ok {
+		return nil, ErrCommitNotFound
+	}
+	return summary, nil
+}
+
+// GetRequestedReviewers mocks the requested reviewers API call
+func (m *MockClient) GetRequestedReviewers(ctx context.Context, owner, repo string, prNumber int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.GetRequestedReviewersCalled = true
+	m.LastOwner = owner
+	m.LastRepo = repo
+	m.LastPRNumber = prNumber
+	return m.RequestedReviewers, m.RequestedError
+}
+
+// RemoveReviewRequests mocks the review request removal API call
+func (m *MockClient) RemoveReviewRequests(ctx context.Context, owner, repo string, prNumber int, reviewers []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.RemoveReviewRequestsCalled = true
+	m.LastOwner = owner
+	m.LastRepo = repo
+	m.LastPRNumber = prNumber
+	m.LastReviewers = reviewers
+	m.LastRemoved = reviewers
+	return m.RemoveError
+}
+
+// CreateIssueComment mocks the issue comment API call
+func (m *MockClient) CreateIssueComment(ctx context.Context, owner, repo string, prNumber int, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CreateIssueCommentCalled = true
+	m.LastOwner = owner
+	m.LastRepo = repo
+	m.LastPRNumber = prNumber
+	m.LastComment = body
+	return m.CommentError
+}
+
+// GetReviewRequestHistory mocks the timeline GraphQL call
+func (m *MockClient) GetReviewRequestHistory(ctx context.Context, owner, repo string, prNumber int) ([]models.ReviewRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.GetReviewRequestHistoryCalled = true
+	m.LastOwner = owner
+	m.LastRepo = repo
+	m.LastPRNumber = prNumber
+	return m.ReviewRequests, m.ReviewRequestsError
+}
+
+// GetUserStatuses mocks the user status GraphQL call
+func (m *MockClient) GetUserStatuses(ctx context.Context, logins []string) (map[string]models.UserStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.GetUserStatusesCalled = true
+	if m.UserStatusesError != nil {
+		return nil, m.UserStatusesError
+	}
+	statuses := make(map[string]models.UserStatus)
+	for _, login := range logins {
+		if status, ok := m.UserStatuses[login]; ok {
+			statuses[login] = status
+		}
+	}
+	return statuses, nil
+}
+
 // Reset clears all tracking data for fresh test
 func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.GetCurrentUserLoginCalled = false
 	m.GetAssignedPRsCalled = false
+	m.SearchQueries = nil
+	m.PreviewCalls = 0
 	m.GetReviewersAndCommentersCalled = false
 	m.ReassignReviewersCalled = false
+	m.GetPullRequestHeadSHACalled = false
+	m.GetReviewsCalled = false
+	m.CompareCommitsCalls = 0
+	m.GetRequestedReviewersCalled = false
+	m.RemoveReviewRequestsCalled = false
+	m.CreateIssueCommentCalled = false
+	m.GetReviewRequestHistoryCalled = false
+	m.GetUserStatusesCalled = false
+	m.LastScope = ""
 	m.LastOwner = ""
 	m.LastRepo = ""
 	m.LastPRNumber = 0
 	m.LastReviewers = nil
+	m.LastRemoved = nil
+	m.LastComment = ""
 }
 
 // MockRepository implements repository information for testing
@@ -0,0 +1,242 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitError is returned when the API budget runs out for longer than the transport is willing to wait.
+// The command line saves the stopped command for resume, and the webhook server keeps the job queued until ResetAt.
+type RateLimitError struct {
+	ResetAt time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("GitHub API rate limit exhausted until %s", e.ResetAt.Local().Format("15:04:05"))
+}
+
+// RetryAfter reports how long to wait before the budget is available again
+func (e *RateLimitError) RetryAfter() time.Duration {
+	return time.Until(e.ResetAt)
+}
+
+// RateLimitTransport retries rate limited requests with exponential backoff and tracks the remaining quota
+type RateLimitTransport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	BaseDelay  time.Duration // first backoff delay when no reset time is known
+	MaxWait    time.Duration // longest acceptable wait; beyond it a RateLimitError is returned
+	Log        io.Writer     // receives the remaining quota; nil disables logging
+
+	now    func() time.Time
+	sleep  func(time.Duration)
+	jitter func(time.Duration) time.Duration
+
+	mu        sync.Mutex
+	remaining int
+	limit     int
+	resetAt   time.Time
+}
+
+// NewRateLimitTransport wraps base with the default retry policy
+func NewRateLimitTransport(base http.RoundTripper, log io.Writer) *RateLimitTransport {
+	return &RateLimitTransport{
+		Base:       base,
+		MaxRetries: 5,
+		BaseDelay:  time.Second,
+		MaxWait:    time.Minute,
+		Log:        log,
+		remaining:  -1,
+	}
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay := t.BaseDelay
+	for attempt := 0; ; attempt++ {
+		// Do not spend a request that is known to fail
+		if wait := t.untilReset(); wait > 0 {
+			if wait > t.MaxWait {
+				return nil, &RateLimitError{ResetAt: t.clock().Add(wait)}
+			}
+			if err := t.pause(req.Context(), wait); err != nil {
+				return nil, err
+			}
+			t.forgetQuota()
+		}
+
+		attemptReq, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.Base.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+		t.record(resp)
+
+		wait, limited := t.retryDelay(resp, delay)
+		if !limited {
+			return resp, nil
+		}
+		if attempt >= t.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		if wait > t.MaxWait {
+			resp.Body.Close()
+			return nil, &RateLimitError{ResetAt: t.clock().Add(wait)}
+		}
+
+		resp.Body.Close()
+		t.logf("rate limited (HTTP %d), retrying in %s", resp.StatusCode, wait.Round(time.Millisecond))
+		if err := t.pause(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		t.forgetQuota()
+		delay *= 2
+	}
+}
+
+// Remaining returns the last known remaining quota and its limit, or -1 when unknown
+func (t *RateLimitTransport) Remaining() (remaining, limit int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.remaining, t.limit
+}
+
+// retryDelay decides whether resp is rate limited and how long to wait before retrying
+func (t *RateLimitTransport) retryDelay(resp *http.Response, backoff time.Duration) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, ok := parseReset(resp.Header); ok {
+			return reset.Sub(t.clock()), true
+		}
+	}
+
+	// A 403 is only a rate limit when GitHub says so; otherwise it is a permission error
+	if resp.StatusCode == http.StatusForbidden && !isSecondaryRateLimit(resp) {
+		return 0, false
+	}
+	return backoff + t.randomJitter(backoff), true
+}
+
+// record keeps the quota reported by the response headers
+func (t *RateLimitTransport) record(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	reset, _ := parseReset(resp.Header)
+
+	t.mu.Lock()
+	t.remaining, t.limit, t.resetAt = remaining, limit, reset
+	t.mu.Unlock()
+
+	t.logf("API quota: %d/%d remaining, resets at %s", remaining, limit, reset.Local().Format("15:04:05"))
+}
+
+// untilReset returns how long to wait when the quota is known to be exhausted
+func (t *RateLimitTransport) untilReset() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.remaining != 0 {
+		return 0
+	}
+	return t.resetAt.Sub(t.clock())
+}
+
+// forgetQuota drops the known quota once its reset time has been waited for
+func (t *RateLimitTransport) forgetQuota() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.remaining = -1
+}
+
+func (t *RateLimitTransport) logf(format string, args ...interface{}) {
+	if t.Log != nil {
+		fmt.Fprintf(t.Log, format+"\n", args...)
+	}
+}
+
+func (t *RateLimitTransport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// pause waits for d unless the request is cancelled first
+func (t *RateLimitTransport) pause(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		t.sleep(d)
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *RateLimitTransport) randomJitter(d time.Duration) time.Duration {
+	if t.jitter != nil {
+		return t.jitter(d)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d))) // #nosec G404 -- jitter does not need a secure source
+}
+
+// rewind returns a request with a fresh body for every attempt after the first
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+func parseReset(header http.Header) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// isSecondaryRateLimit peeks at the error message and restores the body for the caller
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "rate limit")
+}
@@ -0,0 +1,231 @@
+package github
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type fakeResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+// newTestTransport replays responses in order and records the sleeps and request bodies
+func newTestTransport(now time.Time, responses ...fakeResponse) (*RateLimitTransport, *[]time.Duration, *[]string) {
+	var sleeps []time.Duration
+	var bodies []string
+	calls := 0
+
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Body != nil {
+			b, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(b))
+		}
+		r := responses[calls]
+		calls++
+		resp := &http.Response{
+			StatusCode: r.status,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(r.body)),
+		}
+		for k, v := range r.headers {
+			resp.Header.Set(k, v)
+		}
+		return resp, nil
+	})
+
+	transport := NewRateLimitTransport(base, nil)
+	transport.now = func() time.Time { return now }
+	transport.sleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	transport.jitter = func(time.Duration) time.Duration { return 0 }
+	return transport, &sleeps, &bodies
+}
+
+func TestRateLimitTransport_Retries(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reset := strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)
+	ok := fakeResponse{status: http.StatusOK, body: "{}"}
+
+	tests := []struct {
+		name           string
+		responses      []fakeResponse
+		expectedStatus int
+		expectedSleeps []time.Duration
+	}{
+		{
+			name:           "not rate limited",
+			responses:      []fakeResponse{ok},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "429 with Retry-After",
+			responses: []fakeResponse{
+				{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "2"}},
+				ok,
+			},
+			expectedStatus: http.StatusOK,
+			expectedSleeps: []time.Duration{2 * time.Second},
+		},
+		{
+			name: "primary rate limit waits for the reset",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}},
+				ok,
+			},
+			expectedStatus: http.StatusOK,
+			expectedSleeps: []time.Duration{30 * time.Second},
+		},
+		{
+			name: "secondary rate limit backs off exponentially",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, body: `{"message": "You have exceeded a secondary rate limit."}`},
+				{status: http.StatusForbidden, body: `{"message": "You have exceeded a secondary rate limit."}`},
+				ok,
+			},
+			expectedStatus: http.StatusOK,
+			expectedSleeps: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name: "permission errors are not retried",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, body: `{"message": "Resource not accessible by integration"}`},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, sleeps, _ := newTestTransport(now, tt.responses...)
+
+			req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if len(*sleeps) != len(tt.expectedSleeps) {
+				t.Fatalf("Expected sleeps %v, got %v", tt.expectedSleeps, *sleeps)
+			}
+			for i := range *sleeps {
+				if (*sleeps)[i] != tt.expectedSleeps[i] {
+					t.Errorf("Expected sleeps %v, got %v", tt.expectedSleeps, *sleeps)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport_PermissionErrorBodyIsPreserved(t *testing.T) {
+	body := `{"message": "Resource not accessible by integration"}`
+	transport, _, _ := newTestTransport(time.Now(), fakeResponse{status: http.StatusForbidden, body: body})
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	if string(got) != body {
+		t.Errorf("Expected body %q, got %q", body, got)
+	}
+}
+
+func TestRateLimitTransport_ReplaysBody(t *testing.T) {
+	transport, _, bodies := newTestTransport(time.Now(),
+		fakeResponse{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "1"}},
+		fakeResponse{status: http.StatusCreated},
+	)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.github.com/repos/o/r/pulls/1/requested_reviewers", bytes.NewReader([]byte(`{"reviewers":["alice"]}`)))
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(*bodies) != 2 || (*bodies)[0] != (*bodies)[1] {
+		t.Errorf("Expected the body to be sent twice, got %q", *bodies)
+	}
+}
+
+func TestRateLimitTransport_BudgetExhausted(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reset := now.Add(time.Hour)
+	transport, sleeps, _ := newTestTransport(now,
+		fakeResponse{status: http.StatusOK, headers: map[string]string{
+			"X-RateLimit-Remaining": "0",
+			"X-RateLimit-Limit":     "5000",
+			"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+		}},
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remaining, limit := transport.Remaining(); remaining != 0 || limit != 5000 {
+		t.Errorf("Expected 0/5000 remaining, got %d/%d", remaining, limit)
+	}
+
+	// The next request is not sent since the budget will not be back in time
+	_, err := transport.RoundTrip(req)
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	if !rateLimitErr.ResetAt.Equal(reset) {
+		t.Errorf("Expected reset at %v, got %v", reset, rateLimitErr.ResetAt)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("Expected no sleep, got %v", *sleeps)
+	}
+}
+
+func TestRateLimitTransport_GivesUpAfterMaxRetries(t *testing.T) {
+	limited := fakeResponse{status: http.StatusTooManyRequests, headers: map[string]string{"Retry-After": "1"}}
+	transport, sleeps, _ := newTestTransport(time.Now(), limited, limited, limited)
+	transport.MaxRetries = 2
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected the last response to be returned, got %d", resp.StatusCode)
+	}
+	if len(*sleeps) != 2 {
+		t.Errorf("Expected 2 retries, got %v", *sleeps)
+	}
+}
+
+func TestRateLimitTransport_LogsQuota(t *testing.T) {
+	var log bytes.Buffer
+	transport, _, _ := newTestTransport(time.Now(), fakeResponse{status: http.StatusOK, headers: map[string]string{
+		"X-RateLimit-Remaining": "4999",
+		"X-RateLimit-Limit":     "5000",
+		"X-RateLimit-Reset":     "1700000000",
+	}})
+	transport.Log = &log
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/user", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(log.String(), "API quota: 4999/5000 remaining") {
+		t.Errorf("Expected quota in verbose output, got %q", log.String())
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// DashboardRole is how the current user is involved in a PR listed on the dashboard
+type DashboardRole string
+
+const (
+	// RoleAuthor marks PRs the user opened and is waiting on reviewers for
+	RoleAuthor DashboardRole = "author"
+	// RoleReviewer marks PRs waiting on the user's review
+	RoleReviewer DashboardRole = "reviewer"
+)
+
+// Blocker is a reviewer a PR is waiting on
+type Blocker struct {
+	Login string `json:"login"`
+	// Stale is set when the reviewer reviewed an older commit and is not requested again;
+	// otherwise their review is requested and not yet submitted
+	Stale bool `json:"stale,omitempty"`
+	// Since is when the review was requested or the stale review submitted; zero when unknown
+	Since time.Time `json:"since,omitempty"`
+}
+
+// DashboardItem is a PR with the reviewers it is waiting on
+type DashboardItem struct {
+	PR       PullRequestInfo `json:"pr"`
+	Role     DashboardRole   `json:"role"`
+	Blockers []Blocker       `json:"blockers"`
+	// Error is set when the PR could not be read, e.g. without access to its repository
+	Error string `json:"error,omitempty"`
+}
+
+// Waiting returns the earliest known time a blocker started blocking, or zero when none is known
+func (d DashboardItem) Waiting() time.Time {
+	var earliest time.Time
+	for _, blocker := range d.Blockers {
+		if !blocker.Since.IsZero() && (earliest.IsZero() || blocker.Since.Before(earliest)) {
+			earliest = blocker.Since
+		}
+	}
+	return earliest
+}
@@ -1,7 +1,14 @@
 package models
 
+import (
+	"strings"
+	"time"
+)
+
 // PullRequestInfo represents PR metadata
 type PullRequestInfo struct {
+	Owner     string `json:"owner,omitempty"`
+	Repo      string `json:"repo,omitempty"`
 	Number    int    `json:"number"`
 	Title     string `json:"title"`
 	User      string `json:"user"`
@@ -9,6 +16,50 @@ type PullRequestInfo struct {
 	Draft     bool   `json:"draft"`
 	UpdatedAt string `json:"updated_at"`
 	CreatedAt string `json:"created_at"`
+
+	ReviewDecision     string   `json:"review_decision,omitempty"` // APPROVED, CHANGES_REQUESTED or REVIEW_REQUIRED; empty when no review is required
+	ChecksState        string   `json:"checks_state,omitempty"`    // status check rollup of the head commit: SUCCESS, FAILURE, ERROR, PENDING or EXPECTED
+	Mergeable          string   `json:"mergeable,omitempty"`       // MERGEABLE, CONFLICTING or UNKNOWN
+	RequestedReviewers int      `json:"requested_reviewers"`
+	Labels             []string `json:"labels,omitempty"`
+	HeadBranch         string   `json:"head_branch,omitempty"`
+
+	URL          string `json:"url,omitempty"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	ChangedFiles int    `json:"changed_files"`
+}
+
+// PRPreview holds what the PR pickers show about the highlighted PR; it is fetched per PR
+// so that listing PRs stays cheap
+type PRPreview struct {
+	BaseBranch     string        `json:"base_branch,omitempty"`
+	Body           string        `json:"body,omitempty"`            // plain text
+	ReviewRequests []string      `json:"review_requests,omitempty"` // requested logins, and "@org/team" for teams
+	LatestReviews  []Review      `json:"latest_reviews,omitempty"`  // latest review of each reviewer
+	Checks         *CheckSummary `json:"checks,omitempty"`          // nil when the head commit has no checks
+}
+
+// CheckSummary counts the check runs and commit statuses of a commit by outcome
+type CheckSummary struct {
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Pending int `json:"pending"`
+	Skipped int `json:"skipped"`
+}
+
+// Add counts a check run (status and conclusion) or a commit status (state) of a status check rollup
+func (s *CheckSummary) Add(status, conclusion, state string) {
+	switch {
+	case state == "SUCCESS" || conclusion == "SUCCESS":
+		s.Passed++
+	case state == "PENDING" || state == "EXPECTED" || (state == "" && status != "COMPLETED"):
+		s.Pending++
+	case conclusion == "NEUTRAL" || conclusion == "SKIPPED":
+		s.Skipped++
+	default:
+		s.Failed++
+	}
 }
 
 // User represents a GitHub user
@@ -19,10 +70,131 @@ type User struct {
 
 // Review represents a PR review
 type Review struct {
-	User User `json:"user"`
+	User        User   `json:"user"`
+	State       string `json:"state"`
+	CommitID    string `json:"commit_id"`
+	SubmittedAt string `json:"submitted_at"`
 }
 
 // Comment represents a PR comment
 type Comment struct {
 	User User `json:"user"`
 }
+
+// CompareSummary represents the changes between two commits
+type CompareSummary struct {
+	BaseCommit   string `json:"base_commit"`
+	HeadCommit   string `json:"head_commit"`
+	Commits      int    `json:"commits"`
+	FilesChanged int    `json:"files_changed"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+}
+
+// ReviewerCandidate represents a user who can be re-requested for review
+type ReviewerCandidate struct {
+	Login              string          `json:"login"`
+	LastReviewedCommit string          `json:"last_reviewed_commit,omitempty"`
+	Changes            *CompareSummary `json:"changes,omitempty"` // nil when there is nothing to compare
+	Status             *UserStatus     `json:"status,omitempty"`  // nil when the user has not set a status
+	Absence            *Absence        `json:"absence,omitempty"` // set when the reviewer is out of office today
+}
+
+// UserStatus is the status a user sets on their GitHub profile
+type UserStatus struct {
+	Busy    bool   `json:"busy"` // "Busy" is checked, i.e. indicatesLimitedAvailability
+	Message string `json:"message,omitempty"`
+}
+
+// ReviewerStatus represents the review state of a user on a PR
+type ReviewerStatus struct {
+	Login        string      `json:"login"`
+	State        string      `json:"state,omitempty"` // latest review state, empty when not reviewed
+	Pending      bool        `json:"pending"`         // review is currently requested
+	LastActivity string      `json:"last_activity,omitempty"`
+	Status       *UserStatus `json:"status,omitempty"`
+	Absence      *Absence    `json:"absence,omitempty"`
+}
+
+// Absence is a period [From, To) during which a user is out of office
+type Absence struct {
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Availability lists the absences of each user, keyed by lowercase login
+type Availability map[string][]Absence
+
+// AbsentAt returns the absence covering at, if any
+func (a Availability) AbsentAt(login string, at time.Time) (*Absence, bool) {
+	for _, absence := range a[strings.ToLower(login)] {
+		if !at.Before(absence.From) && at.Before(absence.To) {
+			absence := absence
+			return &absence, true
+		}
+	}
+	return nil, false
+}
+
+// ReviewRequest is a ReviewRequestedEvent from the PR timeline
+type ReviewRequest struct {
+	Reviewer    string
+	RequestedAt time.Time
+}
+
+// PRRef identifies a pull request in any repository
+type PRRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// ReassignPlan describes the review requests about to be sent
+type ReassignPlan struct {
+	Owner     string   `json:"owner"`
+	Repo      string   `json:"repo"`
+	PRNumber  int      `json:"pr_number"`
+	Reviewers []string `json:"reviewers"`
+	Warnings  []string `json:"warnings,omitempty"` // shown before asking for confirmation
+	// Substitutions lists the primaries whose requests are removed in favor of the Reviewers
+	Substitutions []Substitution `json:"substitutions,omitempty"`
+}
+
+// Substitution hands a primary reviewer's pending review request over to a backup
+type Substitution struct {
+	Primary string `json:"primary"`
+	Backup  string `json:"backup"`
+	Reason  string `json:"reason,omitempty"` // why the primary is unavailable, empty when handed off on request
+}
+
+// PullRequestEvent is the subset of pull_request and pull_request_review webhook payloads used by this tool
+type PullRequestEvent struct {
+	Action string `json:"action"`
+	Number int    `json:"number"`
+	Review struct {
+		User  User   `json:"user"`
+		State string `json:"state"`
+	} `json:"review"` // only set on pull_request_review events
+	PullRequest struct {
+		Number int    `json:"number"`
+		State  string `json:"state"`
+		Draft  bool   `json:"draft"`
+		User   User   `json:"user"`
+		Head   struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender User `json:"sender"` // user who triggered the event
+}
+
+// PullRequestNumber returns the PR the event refers to, or 0 when there is none
+func (e *PullRequestEvent) PullRequestNumber() int {
+	if e.PullRequest.Number != 0 {
+		return e.PullRequest.Number
+	}
+	return e.Number
+}
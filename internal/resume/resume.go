@@ -0,0 +1,99 @@
+// Package resume saves the command of a run stopped by the API rate limit, so that it can be run again once the quota is back
+package resume
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State is a run stopped by the rate limit
+type State struct {
+	// Args are the command line arguments without the program name
+	Args []string `json:"args"`
+	// Dir is the working directory, which picks the repository of a PR number
+	Dir string `json:"dir"`
+	// ResetAt is when the quota is back
+	ResetAt time.Time `json:"reset_at"`
+	// SavedAt is when the run stopped
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// Ready reports whether the quota is back at now
+func (s State) Ready(now time.Time) bool {
+	return !now.Before(s.ResetAt)
+}
+
+// Store keeps the last stopped run in a JSON file; saving replaces the run saved before
+type Store struct {
+	path string
+}
+
+// NewStore returns the store at path; the file is created on the first save
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the location of the file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Save writes state, replacing the file atomically so that a crash never leaves half of it
+func (s *Store) Save(state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode resume state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create resume state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".resume-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write resume state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write resume state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write resume state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write resume state: %w", err)
+	}
+	return nil
+}
+
+// Load returns the saved state, or nil when no run is waiting to be resumed
+func (s *Store) Load() (*State, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resume state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid resume state %s: %w", s.path, err)
+	}
+	if len(state.Args) == 0 {
+		return nil, fmt.Errorf("invalid resume state %s: no command saved", s.path)
+	}
+	return &state, nil
+}
+
+// Clear deletes the saved state once the run has finished
+func (s *Store) Clear() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to clear resume state: %w", err)
+	}
+	return nil
+}
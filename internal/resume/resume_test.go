@@ -0,0 +1,78 @@
+package resume
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestStore_SaveLoadClear tests that a saved run is read back until it is cleared
+func TestStore_SaveLoadClear(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "state", "resume.json"))
+
+	state, err := store.Load()
+	if err != nil || state != nil {
+		t.Fatalf("Expected nothing saved, got %v, %v", state, err)
+	}
+
+	resetAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	saved := State{Args: []string{"--strategy", "stale", "--org", "acme"}, Dir: "/work/api", ResetAt: resetAt, SavedAt: resetAt.Add(-time.Hour)}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	state, err = store.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state == nil || len(state.Args) != 4 || state.Args[3] != "acme" || state.Dir != "/work/api" || !state.ResetAt.Equal(resetAt) {
+		t.Fatalf("Expected %+v, got %+v", saved, state)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state, err := store.Load(); err != nil || state != nil {
+		t.Errorf("Expected nothing saved after clearing, got %v, %v", state, err)
+	}
+	if err := store.Clear(); err != nil {
+		t.Errorf("Clearing twice should not fail: %v", err)
+	}
+}
+
+// TestStore_LoadInvalid tests that a damaged file is reported rather than resumed
+func TestStore_LoadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "not JSON", data: "{"},
+		{name: "no command", data: `{"args":[],"dir":"/work"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "resume.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatalf("Failed to write %s: %v", path, err)
+			}
+			if _, err := NewStore(path).Load(); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+// TestState_Ready tests that a run can be resumed from its reset time on
+func TestState_Ready(t *testing.T) {
+	resetAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	state := State{ResetAt: resetAt}
+
+	if state.Ready(resetAt.Add(-time.Second)) {
+		t.Error("Expected not ready before the reset")
+	}
+	if !state.Ready(resetAt) {
+		t.Error("Expected ready at the reset")
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// userStatuses fetches the GitHub profile status of each login. Statuses are advisory,
+// so a failure leaves every status unknown rather than blocking the reassignment.
+func (s *ReassignService) userStatuses(ctx context.Context, logins []string) map[string]models.UserStatus {
+	if len(logins) == 0 {
+		return nil
+	}
+	statuses, err := s.client.GetUserStatuses(ctx, logins)
+	if err != nil {
+		return nil
+	}
+	return statuses
+}
+
+// absence returns the configured absence of login covering the current time, if any
+func (s *ReassignService) absence(login string) *models.Absence {
+	absence, _ := s.availability.AbsentAt(login, s.clock())
+	return absence
+}
+
+// unavailable explains why login should not be picked automatically, or returns "" when they are available.
+// Absences always count; a busy status counts unless WithIncludeBusy is set.
+func (s *ReassignService) unavailable(login string, statuses map[string]models.UserStatus) string {
+	return s.unavailableReason(login, statuses, s.includeBusy)
+}
+
+func (s *ReassignService) unavailableReason(login string, statuses map[string]models.UserStatus, includeBusy bool) string {
+	if absence := s.absence(login); absence != nil {
+		return describeAbsence(login, absence)
+	}
+	if status, ok := statuses[login]; ok && status.Busy && !includeBusy {
+		return describeBusy(login, status)
+	}
+	return ""
+}
+
+// pickAvailable keeps the available reviewers and stands in a configured backup for each unavailable one.
+// The notes explain every substitution and every reviewer skipped for want of a backup.
+func (s *ReassignService) pickAvailable(ctx context.Context, reviewers []string, self string, statuses map[string]models.UserStatus) ([]string, []string, error) {
+	taken := make(map[string]bool, len(reviewers))
+	for _, reviewer := range reviewers {
+		taken[strings.ToLower(reviewer)] = true
+	}
+
+	var picked, notes, skipped, away []string
+	for _, reviewer := range reviewers {
+		if s.unavailable(reviewer, statuses) == "" {
+			picked = append(picked, reviewer)
+		} else {
+			away = append(away, reviewer)
+		}
+	}
+
+	backupStatuses := s.userStatuses(ctx, s.backupCandidates(away))
+	for _, reviewer := range away {
+		reason := s.unavailable(reviewer, statuses)
+		backup := s.firstAvailableBackup(reviewer, self, taken, backupStatuses)
+		if backup == "" {
+			skipped = append(skipped, reason)
+			continue
+		}
+		taken[strings.ToLower(backup)] = true
+		picked = append(picked, backup)
+		notes = append(notes, fmt.Sprintf("%s; requesting backup %s instead", reason, backup))
+	}
+
+	if len(picked) == 0 {
+		return nil, notes, fmt.Errorf("%w: %s", ErrNothingToRequest, strings.Join(skipped, "; "))
+	}
+	for _, reason := range skipped {
+		notes = append(notes, reason+"; skipped, no backup is available")
+	}
+	return picked, notes, nil
+}
+
+// availabilityWarnings describes each busy or absent reviewer and suggests their backup
+func (s *ReassignService) availabilityWarnings(ctx context.Context, reviewers []string, self string) []string {
+	statuses := s.userStatuses(ctx, reviewers)
+
+	var away []string
+	for _, reviewer := range reviewers {
+		if s.unavailableReason(reviewer, statuses, false) != "" {
+			away = append(away, reviewer)
+		}
+	}
+	if len(away) == 0 {
+		return nil
+	}
+
+	taken := make(map[string]bool, len(reviewers))
+	for _, reviewer := range reviewers {
+		taken[strings.ToLower(reviewer)] = true
+	}
+	backupStatuses := s.userStatuses(ctx, s.backupCandidates(away))
+
+	warnings := make([]string, 0, len(away))
+	for _, reviewer := range away {
+		warning := s.unavailableReason(reviewer, statuses, false)
+		if backup := s.firstAvailableBackup(reviewer, self, taken, backupStatuses); backup != "" {
+			warning += fmt.Sprintf(" (backup: %s)", backup)
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+// backupsFor returns the configured backups of login, in order of preference
+func (s *ReassignService) backupsFor(login string) []string {
+	for primary, backups := range s.backups {
+		if strings.EqualFold(primary, login) {
+			return backups
+		}
+	}
+	return nil
+}
+
+// backupCandidates lists every backup of the given reviewers so their statuses can be fetched at once
+func (s *ReassignService) backupCandidates(reviewers []string) []string {
+	var candidates []string
+	for _, reviewer := range reviewers {
+		candidates = append(candidates, s.backupsFor(reviewer)...)
+	}
+	return candidates
+}
+
+// firstAvailableBackup returns the first backup of login who is available and not already taken
+func (s *ReassignService) firstAvailableBackup(login, self string, taken map[string]bool, statuses map[string]models.UserStatus) string {
+	for _, backup := range s.backupsFor(login) {
+		if strings.EqualFold(backup, self) || taken[strings.ToLower(backup)] {
+			continue
+		}
+		if s.unavailable(backup, statuses) == "" {
+			return backup
+		}
+	}
+	return ""
+}
+
+// statusesOf collects the statuses attached to candidates
+func statusesOf(candidates []models.ReviewerCandidate) map[string]models.UserStatus {
+	statuses := make(map[string]models.UserStatus)
+	for _, candidate := range candidates {
+		if candidate.Status != nil {
+			statuses[candidate.Login] = *candidate.Status
+		}
+	}
+	return statuses
+}
+
+// statusOf returns the status of login, or nil when none is set
+func statusOf(statuses map[string]models.UserStatus, login string) *models.UserStatus {
+	status, ok := statuses[login]
+	if !ok {
+		return nil
+	}
+	return &status
+}
+
+func describeBusy(login string, status models.UserStatus) string {
+	if status.Message != "" {
+		return fmt.Sprintf("%s is busy: %s", login, status.Message)
+	}
+	return fmt.Sprintf("%s is busy", login)
+}
+
+func describeAbsence(login string, absence *models.Absence) string {
+	return login + " is " + ui.FormatAbsence(absence)
+}
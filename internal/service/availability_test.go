@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// TestProcessReassignmentBusy tests that strategies skip busy reviewers and prompts warn about them
+func TestReassignService_ProcessReassignmentBusy(t *testing.T) {
+	busy := map[string]models.UserStatus{
+		"user1": {Busy: true, Message: "On vacation"},
+		"user2": {Message: "Focusing"},
+	}
+	staleReviews := []models.Review{
+		{User: models.User{Login: "user1"}, CommitID: "old"},
+		{User: models.User{Login: "user2"}, CommitID: "old"},
+	}
+
+	tests := []struct {
+		name            string
+		opts            []Option
+		statusesError   error
+		expectReviewers []string
+		expectWarnings  []string
+		expectErr       error
+	}{
+		{
+			name:            "all skips busy",
+			opts:            []Option{WithStrategy(StrategyAll), WithAutoConfirm(true)},
+			expectReviewers: []string{"user2"},
+			expectWarnings:  []string{"user1 is busy: On vacation; skipped, no backup is available"},
+		},
+		{
+			name:            "stale skips busy",
+			opts:            []Option{WithStrategy(StrategyStale), WithAutoConfirm(true)},
+			expectReviewers: []string{"user2"},
+			expectWarnings:  []string{"user1 is busy: On vacation; skipped, no backup is available"},
+		},
+		{
+			name:            "include busy",
+			opts:            []Option{WithStrategy(StrategyAll), WithAutoConfirm(true), WithIncludeBusy(true)},
+			expectReviewers: []string{"user1", "user2"},
+		},
+		{
+			name:            "hand-picked busy reviewer is warned about",
+			opts:            []Option{WithReviewers([]string{"user1"})},
+			expectReviewers: []string{"user1"},
+			expectWarnings:  []string{"user1 is busy: On vacation"},
+		},
+		{
+			name:            "statuses are advisory",
+			opts:            []Option{WithStrategy(StrategyAll), WithAutoConfirm(true)},
+			statusesError:   github.NewNetworkError(),
+			expectReviewers: []string{"user1", "user2"},
+		},
+		{
+			name:      "everyone busy",
+			opts:      []Option{WithStrategy(StrategyAll), WithAutoConfirm(true)},
+			expectErr: ErrNothingToRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{
+				CurrentUser:         "currentuser",
+				ReviewersCommenters: []string{"user1", "user2"},
+				HeadSHA:             "head",
+				Reviews:             staleReviews,
+				UserStatuses:        busy,
+				UserStatusesError:   tt.statusesError,
+			}
+			if tt.expectErr != nil {
+				client.UserStatuses = map[string]models.UserStatus{"user1": {Busy: true}, "user2": {Busy: true}}
+			}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			prompter := &ui.MockPrompter{ConfirmedSelection: true}
+			service := NewReassignService(client, repo, prompter, tt.opts...)
+
+			plan, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("Expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(plan.Reviewers) != len(tt.expectReviewers) {
+				t.Fatalf("Expected reviewers %v, got %v", tt.expectReviewers, plan.Reviewers)
+			}
+			for i := range tt.expectReviewers {
+				if plan.Reviewers[i] != tt.expectReviewers[i] {
+					t.Errorf("Expected reviewers %v, got %v", tt.expectReviewers, plan.Reviewers)
+				}
+			}
+			if len(plan.Warnings) != len(tt.expectWarnings) {
+				t.Fatalf("Expected warnings %v, got %v", tt.expectWarnings, plan.Warnings)
+			}
+			for i := range tt.expectWarnings {
+				if plan.Warnings[i] != tt.expectWarnings[i] {
+					t.Errorf("Expected warnings %v, got %v", tt.expectWarnings, plan.Warnings)
+				}
+			}
+		})
+	}
+}
+
+// TestReassignBusy tests that reviewers picked in the TUI are warned about and confirmed like prompted ones
+func TestReassignService_ReassignBusy(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		confirmed     bool
+		expectConfirm bool
+		expectRequest bool
+	}{
+		{name: "confirmed", confirmed: true, expectConfirm: true, expectRequest: true},
+		{name: "declined", expectConfirm: true},
+		{name: "auto confirmed", opts: []Option{WithAutoConfirm(true)}, expectRequest: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{UserStatuses: map[string]models.UserStatus{"user1": {Busy: true, Message: "On vacation"}}}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			prompter := &ui.MockPrompter{ConfirmedSelection: tt.confirmed}
+			service := NewReassignService(client, repo, prompter, tt.opts...)
+
+			_, err := service.Reassign(context.Background(), 123, []string{"user1", "user2"}, "currentuser")
+
+			if tt.expectRequest && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !tt.expectRequest && err == nil {
+				t.Fatalf("Expected error but got none")
+			}
+			if client.ReassignReviewersCalled != tt.expectRequest {
+				t.Errorf("Expected reviewers requested = %v, got %v", tt.expectRequest, client.ReassignReviewersCalled)
+			}
+			if prompter.ConfirmSelectionCalled != tt.expectConfirm {
+				t.Fatalf("Expected confirmation asked = %v, got %v", tt.expectConfirm, prompter.ConfirmSelectionCalled)
+			}
+			if tt.expectConfirm {
+				if warnings := prompter.LastPlan.Warnings; len(warnings) != 1 || warnings[0] != "user1 is busy: On vacation" {
+					t.Errorf("Expected a warning about user1, got %v", warnings)
+				}
+			}
+		})
+	}
+}
+
+// TestGetReviewerCandidatesStatus tests that each candidate carries their GitHub status
+func TestReassignService_GetReviewerCandidatesStatus(t *testing.T) {
+	client := &github.MockClient{
+		HeadSHA:      "head",
+		UserStatuses: map[string]models.UserStatus{"user1": {Busy: true, Message: "On vacation"}},
+	}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{})
+
+	candidates, err := service.GetReviewerCandidates(context.Background(), 123, []string{"user1", "user2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if candidates[0].Status == nil || !candidates[0].Status.Busy {
+		t.Errorf("Expected user1 to be busy, got %+v", candidates[0].Status)
+	}
+	if candidates[1].Status != nil {
+		t.Errorf("Expected no status for user2, got %+v", candidates[1].Status)
+	}
+}
+
+// TestProcessReassignmentAbsent tests that absent reviewers are replaced by their backups
+func TestReassignService_ProcessReassignmentAbsent(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	availability := models.Availability{
+		"user1": {{From: now.Add(-24 * time.Hour), To: now.Add(24 * time.Hour), Reason: "Vacation"}},
+		"user3": {{From: now.Add(-time.Hour), To: now.Add(time.Hour)}},
+	}
+	away := "user1 is " + ui.FormatAbsence(&availability["user1"][0])
+
+	tests := []struct {
+		name            string
+		opts            []Option
+		statuses        map[string]models.UserStatus
+		expectReviewers []string
+		expectWarnings  []string
+		expectErr       error
+	}{
+		{
+			name:            "absent reviewer is skipped",
+			opts:            []Option{WithStrategy(StrategyAll), WithAutoConfirm(true)},
+			expectReviewers: []string{"user2"},
+			expectWarnings:  []string{away + "; skipped, no backup is available"},
+		},
+		{
+			name:            "backup stands in",
+			opts:            []Option{WithStrategy(StrategyAll), WithAutoConfirm(true), WithBackups(map[string][]string{"User1": {"user4"}})},
+			expectReviewers: []string{"user2", "user4"},
+			expectWarnings:  []string{away + "; requesting backup user4 instead"},
+		},
+		{
+			name: "unavailable backups are passed over",
+			opts: []Option{WithStrategy(StrategyAll), WithAutoConfirm(true),
+				WithBackups(map[string][]string{"user1": {"currentuser", "user2", "user3", "user5", "user4"}})},
+			statuses:        map[string]models.UserStatus{"user5": {Busy: true}},
+			expectReviewers: []string{"user2", "user4"},
+			expectWarnings:  []string{away + "; requesting backup user4 instead"},
+		},
+		{
+			name:            "hand-picked absent reviewer is warned about",
+			opts:            []Option{WithReviewers([]string{"user1"}), WithBackups(map[string][]string{"user1": {"user4"}})},
+			expectReviewers: []string{"user1"},
+			expectWarnings:  []string{away + " (backup: user4)"},
+		},
+		{
+			name:      "everyone absent",
+			opts:      []Option{WithStrategy(StrategyAll), WithAutoConfirm(true)},
+			statuses:  map[string]models.UserStatus{"user2": {Busy: true}},
+			expectErr: ErrNothingToRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{
+				CurrentUser:         "currentuser",
+				ReviewersCommenters: []string{"user1", "user2"},
+				UserStatuses:        tt.statuses,
+			}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			prompter := &ui.MockPrompter{ConfirmedSelection: true}
+			service := NewReassignService(client, repo, prompter, append(tt.opts, WithAvailability(availability))...)
+			service.now = func() time.Time { return now }
+
+			plan, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("Expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if strings.Join(plan.Reviewers, ",") != strings.Join(tt.expectReviewers, ",") {
+				t.Errorf("Expected reviewers %v, got %v", tt.expectReviewers, plan.Reviewers)
+			}
+			if strings.Join(plan.Warnings, "|") != strings.Join(tt.expectWarnings, "|") {
+				t.Errorf("Expected warnings %v, got %v", tt.expectWarnings, plan.Warnings)
+			}
+		})
+	}
+}
+
+// TestGetReviewerCandidatesAbsence tests that each candidate carries their current absence
+func TestReassignService_GetReviewerCandidatesAbsence(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	client := &github.MockClient{HeadSHA: "head"}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{}, WithAvailability(models.Availability{
+		"user1": {{From: now.Add(-time.Hour), To: now.Add(time.Hour), Reason: "Offsite"}},
+		"user2": {{From: now.Add(time.Hour), To: now.Add(2 * time.Hour)}},
+	}))
+	service.now = func() time.Time { return now }
+
+	candidates, err := service.GetReviewerCandidates(context.Background(), 123, []string{"user1", "user2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if candidates[0].Absence == nil || candidates[0].Absence.Reason != "Offsite" {
+		t.Errorf("Expected user1 to be away, got %+v", candidates[0].Absence)
+	}
+	if candidates[1].Absence != nil {
+		t.Errorf("Expected user2 to be available, got %+v", candidates[1].Absence)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrCooldown is returned when a reviewer was requested on the PR too recently
+var ErrCooldown = errors.New("reviewer requested too recently")
+
+// applyCooldown drops the reviewers requested on the PR within the cooldown who have not reviewed since,
+// based on the PR timeline. Strategies skip them and return a note for each so automation does not fail
+// on every push; a hand-picked reviewer is refused with ErrCooldown instead.
+func (s *ReassignService) applyCooldown(ctx context.Context, prNumber int, reviewers []string) ([]string, []string, error) {
+	if s.cooldown <= 0 || s.force {
+		return reviewers, nil, nil
+	}
+
+	owner, name := s.repo.GetOwner(), s.repo.GetName()
+	var history []models.ReviewRequest
+	var reviews []models.Review
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		history, err = s.client.GetReviewRequestHistory(gctx, owner, name, prNumber)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		reviews, err = s.client.GetReviews(gctx, owner, name, prNumber)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, nil, fmt.Errorf("failed to check the cooldown: %w", err)
+	}
+
+	requestedAt := unansweredRequests(lastRequested(history), reviews)
+	now := s.clock()
+	var allowed, blocked []string
+	for _, reviewer := range reviewers {
+		last, ok := requestedAt[strings.ToLower(reviewer)]
+		if ok && now.Sub(last) < s.cooldown {
+			blocked = append(blocked, fmt.Sprintf("%s was requested %s ago and has not reviewed since", reviewer, now.Sub(last).Round(time.Minute)))
+			continue
+		}
+		allowed = append(allowed, reviewer)
+	}
+
+	switch {
+	case len(blocked) == 0:
+		return reviewers, nil, nil
+	case s.mode() != audit.ModeInteractive && len(allowed) > 0:
+		notes := make([]string, len(blocked))
+		for i, reason := range blocked {
+			notes[i] = reason + "; skipped by the cooldown"
+		}
+		return allowed, notes, nil
+	case s.mode() != audit.ModeInteractive:
+		return nil, nil, fmt.Errorf("%w: %s", ErrNothingToRequest, strings.Join(blocked, "; "))
+	default:
+		return nil, nil, fmt.Errorf("%w: %s (cooldown %s); use --force to request anyway", ErrCooldown, strings.Join(blocked, "; "), s.cooldown)
+	}
+}
+
+// lastRequested maps each lowercase login to the time of their latest review request
+func lastRequested(history []models.ReviewRequest) map[string]time.Time {
+	requestedAt := make(map[string]time.Time, len(history))
+	for _, request := range history {
+		login := strings.ToLower(request.Reviewer)
+		if request.RequestedAt.After(requestedAt[login]) {
+			requestedAt[login] = request.RequestedAt
+		}
+	}
+	return requestedAt
+}
+
+// unansweredRequests drops the requests a reviewer has answered with a review submitted since
+func unansweredRequests(requestedAt map[string]time.Time, reviews []models.Review) map[string]time.Time {
+	for _, review := range reviews {
+		login := strings.ToLower(review.User.Login)
+		last, ok := requestedAt[login]
+		if !ok {
+			continue
+		}
+		submitted, err := time.Parse(time.RFC3339, review.SubmittedAt)
+		if err == nil && !submitted.Before(last) {
+			delete(requestedAt, login)
+		}
+	}
+	return requestedAt
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// TestReassignCooldown tests that recently requested reviewers are not requested again
+func TestReassignService_ReassignCooldown(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	history := []models.ReviewRequest{
+		{Reviewer: "user1", RequestedAt: now.Add(-20 * time.Hour)},
+		{Reviewer: "user1", RequestedAt: now.Add(-2 * time.Hour)},
+		{Reviewer: "user2", RequestedAt: now.Add(-13 * time.Hour)},
+		{Reviewer: "user4", RequestedAt: now.Add(-3 * time.Hour)},
+	}
+	// user4 answered their request, so it no longer counts
+	reviews := []models.Review{
+		{User: models.User{Login: "user1"}, SubmittedAt: now.Add(-19 * time.Hour).Format(time.RFC3339)},
+		{User: models.User{Login: "user4"}, SubmittedAt: now.Add(-time.Hour).Format(time.RFC3339)},
+	}
+
+	tests := []struct {
+		name            string
+		opts            []Option
+		reviewers       []string
+		expectErr       error
+		expectRequested []string
+		expectHistory   bool
+	}{
+		{
+			name:            "disabled",
+			reviewers:       []string{"user1"},
+			expectRequested: []string{"user1"},
+		},
+		{
+			name:            "outside cooldown",
+			opts:            []Option{WithCooldown(12 * time.Hour)},
+			reviewers:       []string{"user2", "user3"},
+			expectRequested: []string{"user2", "user3"},
+			expectHistory:   true,
+		},
+		{
+			name:            "reviewed since the request",
+			opts:            []Option{WithCooldown(12 * time.Hour)},
+			reviewers:       []string{"user4"},
+			expectRequested: []string{"user4"},
+			expectHistory:   true,
+		},
+		{
+			name:          "hand-picked reviewer within cooldown",
+			opts:          []Option{WithCooldown(12 * time.Hour)},
+			reviewers:     []string{"user1", "user2"},
+			expectErr:     ErrCooldown,
+			expectHistory: true,
+		},
+		{
+			name:            "forced",
+			opts:            []Option{WithCooldown(12 * time.Hour), WithForce(true)},
+			reviewers:       []string{"user1"},
+			expectRequested: []string{"user1"},
+		},
+		{
+			name:            "strategy skips reviewers within cooldown",
+			opts:            []Option{WithCooldown(12 * time.Hour), WithStrategy(StrategyStale)},
+			reviewers:       []string{"user1", "user2"},
+			expectRequested: []string{"user2"},
+			expectHistory:   true,
+		},
+		{
+			name:          "strategy with every reviewer within cooldown",
+			opts:          []Option{WithCooldown(12 * time.Hour), WithStrategy(StrategyStale)},
+			reviewers:     []string{"USER1"},
+			expectErr:     ErrNothingToRequest,
+			expectHistory: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{ReviewRequests: history, Reviews: reviews}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			service := NewReassignService(client, repo, &ui.MockPrompter{ConfirmedSelection: true}, tt.opts...)
+			service.now = func() time.Time { return now }
+
+			plan, err := service.Reassign(context.Background(), 123, tt.reviewers, "currentuser")
+
+			if client.GetReviewRequestHistoryCalled != tt.expectHistory {
+				t.Errorf("Expected history fetched = %v, got %v", tt.expectHistory, client.GetReviewRequestHistoryCalled)
+			}
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("Expected %v, got %v", tt.expectErr, err)
+				}
+				if client.ReassignReviewersCalled {
+					t.Errorf("Reviewers should not be requested")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(client.LastReviewers) != len(tt.expectRequested) {
+				t.Fatalf("Expected %v to be requested, got %v", tt.expectRequested, client.LastReviewers)
+			}
+			for i := range tt.expectRequested {
+				if client.LastReviewers[i] != tt.expectRequested[i] {
+					t.Errorf("Expected %v to be requested, got %v", tt.expectRequested, client.LastReviewers)
+				}
+			}
+			// Every reviewer left out by the cooldown is reported
+			if skipped := len(tt.reviewers) - len(tt.expectRequested); len(plan.Warnings) != skipped {
+				t.Errorf("Expected %d skipped reviewers to be reported, got %v", skipped, plan.Warnings)
+			}
+		})
+	}
+}
+
+// TestProcessReassignmentCooldown tests that the confirmed plan leaves out reviewers within the cooldown
+func TestReassignService_ProcessReassignmentCooldown(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := &github.MockClient{
+		CurrentUser:         "currentuser",
+		ReviewersCommenters: []string{"user1", "user2"},
+		ReviewRequests:      []models.ReviewRequest{{Reviewer: "user1", RequestedAt: now.Add(-time.Hour)}},
+	}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	prompter := &ui.MockPrompter{ConfirmedSelection: true}
+	service := NewReassignService(client, repo, prompter, WithStrategy(StrategyAll), WithCooldown(12*time.Hour))
+	service.now = func() time.Time { return now }
+
+	plan, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plan.Reviewers) != 1 || plan.Reviewers[0] != "user2" {
+		t.Errorf("Expected only user2 in the plan, got %v", plan.Reviewers)
+	}
+	if len(prompter.LastPlan.Reviewers) != 1 {
+		t.Errorf("Expected the confirmation to show only user2, got %v", prompter.LastPlan.Reviewers)
+	}
+	if len(plan.Warnings) != 1 || !containsString(plan.Warnings[0], "user1 was requested 1h0m0s ago") {
+		t.Errorf("Expected a warning that user1 was skipped, got %v", plan.Warnings)
+	}
+}
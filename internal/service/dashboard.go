@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"golang.org/x/sync/errgroup"
+)
+
+// DashboardOptions scopes the searches behind the dashboard
+type DashboardOptions struct {
+	// Query adds search qualifiers to both searches, e.g. "org:acme" or "repo:acme/api"
+	Query string
+	// Limit caps the PRs read per search; 0 reads every page
+	Limit int
+}
+
+// Dashboard lists the open PRs the current user authored that wait on requested or stale reviews,
+// followed by the PRs waiting on the user's review, each longest-waiting first
+func (s *ReassignService) Dashboard(ctx context.Context, opts DashboardOptions) ([]models.DashboardItem, string, error) {
+	self, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	base := strings.TrimSpace("is:pr is:open archived:false " + opts.Query)
+	var authored, requested []models.PullRequestInfo
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		authored, err = s.client.SearchPullRequests(gctx, base+" draft:false author:"+self, opts.Limit)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		requested, err = s.client.SearchPullRequests(gctx, base+" review-requested:"+self, opts.Limit)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, "", fmt.Errorf("failed to search pull requests: %w", err)
+	}
+
+	// A PR that cannot be read, e.g. in a repository behind SAML or a deleted fork, is listed with its error
+	// instead of failing the whole dashboard
+	authorItems := make([]models.DashboardItem, len(authored))
+	reviewerItems := make([]models.DashboardItem, len(requested))
+	var wg sync.WaitGroup
+	for i, pr := range authored {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			blockers, err := s.ForRepository(pr.Owner, pr.Repo).blockers(ctx, pr.Number, self)
+			authorItems[i] = models.DashboardItem{PR: pr, Role: models.RoleAuthor, Blockers: blockers, Error: errorText(err)}
+		}()
+	}
+	for i, pr := range requested {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			history, err := s.client.GetReviewRequestHistory(ctx, pr.Owner, pr.Repo, pr.Number)
+			// Team requests leave no request for the user in the timeline
+			blocker := models.Blocker{Login: self, Since: lastRequested(history)[strings.ToLower(self)]}
+			reviewerItems[i] = models.DashboardItem{PR: pr, Role: models.RoleReviewer, Blockers: []models.Blocker{blocker}, Error: errorText(err)}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var items []models.DashboardItem
+	for _, item := range authorItems {
+		if len(item.Blockers) > 0 || item.Error != "" {
+			items = append(items, item)
+		}
+	}
+	sortByWaiting(items)
+	sortByWaiting(reviewerItems)
+	return append(items, reviewerItems...), self, nil
+}
+
+// ReRequest re-requests the reviewers blocking a PR the current user authored
+func (s *ReassignService) ReRequest(ctx context.Context, item models.DashboardItem, self string) ([]string, error) {
+	if item.Error != "" {
+		return nil, fmt.Errorf("%s/%s#%d could not be loaded: %s", item.PR.Owner, item.PR.Repo, item.PR.Number, item.Error)
+	}
+	if item.Role != models.RoleAuthor {
+		return nil, fmt.Errorf("%s/%s#%d is waiting on your own review", item.PR.Owner, item.PR.Repo, item.PR.Number)
+	}
+
+	reviewers := make([]string, len(item.Blockers))
+	for i, blocker := range item.Blockers {
+		reviewers[i] = blocker.Login
+	}
+
+	target := s.ForRepository(item.PR.Owner, item.PR.Repo)
+	if err := target.ValidateReviewers(reviewers, self); err != nil {
+		return nil, err
+	}
+	reviewers, _, err := target.applyCooldown(ctx, item.PR.Number, reviewers)
+	if err != nil {
+		return nil, err
+	}
+	if err := target.request(ctx, item.PR.Number, reviewers, self); err != nil {
+		return nil, err
+	}
+	return reviewers, nil
+}
+
+// blockers returns the requested reviewers and the reviewers whose latest review predates the head commit
+func (s *ReassignService) blockers(ctx context.Context, prNumber int, self string) ([]models.Blocker, error) {
+	owner, name := s.repo.GetOwner(), s.repo.GetName()
+
+	var head string
+	var requested []string
+	var reviews []models.Review
+	var history []models.ReviewRequest
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		head, err = s.client.GetPullRequestHeadSHA(gctx, owner, name, prNumber)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		requested, err = s.client.GetRequestedReviewers(gctx, owner, name, prNumber)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		reviews, err = s.client.GetReviews(gctx, owner, name, prNumber)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		history, err = s.client.GetReviewRequestHistory(gctx, owner, name, prNumber)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	requestedAt := lastRequested(history)
+	pending := make(map[string]bool, len(requested))
+	var blockers []models.Blocker
+	for _, login := range requested {
+		pending[strings.ToLower(login)] = true
+		blockers = append(blockers, models.Blocker{Login: login, Since: requestedAt[strings.ToLower(login)]})
+	}
+
+	// Reviews are returned in chronological order, so the last one wins
+	latest := make(map[string]models.Review)
+	var order []string
+	for _, review := range reviews {
+		login := review.User.Login
+		if review.CommitID == "" || strings.EqualFold(login, self) || pending[strings.ToLower(login)] {
+			continue
+		}
+		if _, ok := latest[login]; !ok {
+			order = append(order, login)
+		}
+		latest[login] = review
+	}
+	for _, login := range order {
+		review := latest[login]
+		if review.CommitID == head {
+			continue
+		}
+		submitted, _ := time.Parse(time.RFC3339, review.SubmittedAt)
+		blockers = append(blockers, models.Blocker{Login: login, Stale: true, Since: submitted})
+	}
+	return blockers, nil
+}
+
+// ForRepository returns a copy of the service working on another repository; an empty owner keeps the current one
+func (s *ReassignService) ForRepository(owner, name string) *ReassignService {
+	if owner == "" || (owner == s.repo.GetOwner() && name == s.repo.GetName()) {
+		return s
+	}
+	target := *s
+	target.repo = &github.Repository{Owner: owner, Name: name}
+	return &target
+}
+
+// errorText is the message of err, or empty without an error
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// sortByWaiting orders items longest-waiting first, with unknown waits last
+func sortByWaiting(items []models.DashboardItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Waiting(), items[j].Waiting()
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.Before(b)
+	})
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// TestDashboard tests listing authored PRs with blockers and PRs waiting on the user
+func TestReassignService_Dashboard(t *testing.T) {
+	requestedAt := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	client := &github.MockClient{
+		CurrentUser: "me",
+		SearchResults: map[string][]models.PullRequestInfo{
+			"is:pr is:open archived:false org:acme draft:false author:me": {
+				{Owner: "acme", Repo: "api", Number: 1, Title: "Mine"},
+			},
+			"is:pr is:open archived:false org:acme review-requested:me": {
+				{Owner: "acme", Repo: "web", Number: 2, Title: "Theirs"},
+			},
+		},
+		HeadSHA:            "head",
+		RequestedReviewers: []string{"alice"},
+		Reviews: []models.Review{
+			{User: models.User{Login: "bob"}, CommitID: "old", SubmittedAt: "2024-05-01T10:00:00Z"},
+			{User: models.User{Login: "carol"}, CommitID: "old"},
+			{User: models.User{Login: "carol"}, CommitID: "head"},
+			{User: models.User{Login: "alice"}, CommitID: "old"},
+		},
+		ReviewRequests: []models.ReviewRequest{
+			{Reviewer: "alice", RequestedAt: requestedAt.Add(-time.Hour)},
+			{Reviewer: "alice", RequestedAt: requestedAt},
+			{Reviewer: "me", RequestedAt: requestedAt},
+		},
+	}
+	service := NewReassignService(client, &github.Repository{}, &ui.MockPrompter{})
+
+	items, self, err := service.Dashboard(context.Background(), DashboardOptions{Query: "org:acme"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if self != "me" {
+		t.Errorf("Expected self me, got %s", self)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %+v", items)
+	}
+
+	authored := items[0]
+	if authored.Role != models.RoleAuthor || authored.PR.Number != 1 {
+		t.Errorf("Expected the authored PR first, got %+v", authored)
+	}
+	expected := []models.Blocker{
+		{Login: "alice", Since: requestedAt},
+		{Login: "bob", Stale: true, Since: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	if len(authored.Blockers) != len(expected) {
+		t.Fatalf("Expected blockers %+v, got %+v", expected, authored.Blockers)
+	}
+	for i := range expected {
+		if authored.Blockers[i] != expected[i] {
+			t.Errorf("Expected blockers %+v, got %+v", expected, authored.Blockers)
+		}
+	}
+	if !authored.Waiting().Equal(expected[1].Since) {
+		t.Errorf("Expected to wait since the stale review, got %v", authored.Waiting())
+	}
+
+	reviewer := items[1]
+	if reviewer.Role != models.RoleReviewer || len(reviewer.Blockers) != 1 || reviewer.Blockers[0].Login != "me" || !reviewer.Blockers[0].Since.Equal(requestedAt) {
+		t.Errorf("Unexpected reviewer item: %+v", reviewer)
+	}
+
+	// Authored PRs nobody blocks are left out
+	client.RequestedReviewers = nil
+	client.Reviews = nil
+	items, _, err = service.Dashboard(context.Background(), DashboardOptions{Query: "org:acme"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].Role != models.RoleReviewer {
+		t.Errorf("Expected only the reviewer item, got %+v", items)
+	}
+
+	// A PR that cannot be read is listed with its error instead of failing the dashboard
+	client.HeadSHAError = errors.New("HTTP 404: Not Found")
+	items, _, err = service.Dashboard(context.Background(), DashboardOptions{Query: "org:acme"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].Role != models.RoleAuthor || !strings.Contains(items[0].Error, "404") || items[1].Error != "" {
+		t.Errorf("Expected the authored PR to carry its error, got %+v", items)
+	}
+	if _, err := service.ReRequest(context.Background(), items[0], "me"); err == nil {
+		t.Error("Expected a PR that failed to load not to be re-requested")
+	}
+	client.HeadSHAError = nil
+
+	client.SearchError = github.NewNetworkError()
+	if _, _, err := service.Dashboard(context.Background(), DashboardOptions{}); err == nil {
+		t.Error("Expected the search error to be returned")
+	}
+}
+
+// TestReRequest tests re-requesting the blockers of a dashboard item in its own repository
+func TestReassignService_ReRequest(t *testing.T) {
+	client := &github.MockClient{}
+	service := NewReassignService(client, &github.Repository{}, &ui.MockPrompter{})
+	item := models.DashboardItem{
+		PR:       models.PullRequestInfo{Owner: "acme", Repo: "api", Number: 7},
+		Role:     models.RoleAuthor,
+		Blockers: []models.Blocker{{Login: "alice"}, {Login: "bob", Stale: true}},
+	}
+
+	reviewers, err := service.ReRequest(context.Background(), item, "me")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(reviewers, ",") != "alice,bob" || client.LastOwner != "acme" || client.LastRepo != "api" || client.LastPRNumber != 7 {
+		t.Errorf("Unexpected request: %v on %s/%s#%d", reviewers, client.LastOwner, client.LastRepo, client.LastPRNumber)
+	}
+
+	item.Role = models.RoleReviewer
+	if _, err := service.ReRequest(context.Background(), item, "me"); err == nil {
+		t.Error("Expected PRs waiting on the user to be refused")
+	}
+
+	// The cooldown applies as for any hand-picked reviewer
+	now := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	client.ReviewRequests = []models.ReviewRequest{{Reviewer: "alice", RequestedAt: now.Add(-time.Hour)}}
+	service = NewReassignService(client, &github.Repository{}, &ui.MockPrompter{}, WithCooldown(12*time.Hour))
+	service.now = func() time.Time { return now }
+	item.Role = models.RoleAuthor
+	if _, err := service.ReRequest(context.Background(), item, "me"); !errors.Is(err, ErrCooldown) {
+		t.Errorf("Expected ErrCooldown, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// ErrIgnoredEvent is returned for events that never trigger a re-request
+var ErrIgnoredEvent = errors.New("event ignored")
+
+// HandlePullRequestEvent re-requests the stale reviewers after new commits are pushed to a PR
+func (s *ReassignService) HandlePullRequestEvent(ctx context.Context, event *models.PullRequestEvent) (*models.ReassignPlan, error) {
+	switch {
+	case event.Action != "synchronize":
+		return nil, fmt.Errorf("%w: action %q is not synchronize", ErrIgnoredEvent, event.Action)
+	case event.PullRequestNumber() == 0:
+		return nil, fmt.Errorf("%w: payload does not reference a pull request", ErrIgnoredEvent)
+	case event.PullRequest.State != "" && event.PullRequest.State != "open":
+		return nil, fmt.Errorf("%w: pull request is %s", ErrIgnoredEvent, event.PullRequest.State)
+	case event.PullRequest.Draft:
+		return nil, fmt.Errorf("%w: pull request is a draft", ErrIgnoredEvent)
+	}
+
+	// The author cannot review their own PR, so they play the part of the current user
+	handler := *s
+	handler.strategy = StrategyStale
+	handler.autoConfirm = true
+	handler.reviewers = nil
+	if author := event.PullRequest.User.Login; author != "" {
+		handler.self, handler.selfKnown = author, true
+	}
+
+	return handler.ProcessReassignment(ctx, []string{"handle-event", strconv.Itoa(event.PullRequestNumber())})
+}
+
+// HandlePullRequestReviewEvent re-requests a reviewer whose review was dismissed
+func (s *ReassignService) HandlePullRequestReviewEvent(ctx context.Context, event *models.PullRequestEvent) (*models.ReassignPlan, error) {
+	reviewer := event.Review.User.Login
+	switch {
+	case event.Action != "dismissed":
+		return nil, fmt.Errorf("%w: review action %q is not dismissed", ErrIgnoredEvent, event.Action)
+	case event.PullRequestNumber() == 0:
+		return nil, fmt.Errorf("%w: payload does not reference a pull request", ErrIgnoredEvent)
+	case event.PullRequest.State != "" && event.PullRequest.State != "open":
+		return nil, fmt.Errorf("%w: pull request is %s", ErrIgnoredEvent, event.PullRequest.State)
+	case event.PullRequest.Draft:
+		return nil, fmt.Errorf("%w: pull request is a draft", ErrIgnoredEvent)
+	case reviewer == "" || event.Review.User.Type == "Bot" || reviewer == event.PullRequest.User.Login:
+		return nil, fmt.Errorf("%w: review by %q cannot be re-requested", ErrIgnoredEvent, reviewer)
+	}
+
+	// Nobody picked the reviewer by hand, and nobody is there to confirm
+	handler := *s
+	handler.autoConfirm = true
+	if handler.auditMode == "" {
+		handler.auditMode = audit.ModeAuto
+	}
+	return handler.Reassign(ctx, event.PullRequestNumber(), []string{reviewer}, event.PullRequest.User.Login)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ci"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// TestHandlePullRequestEvent tests re-requesting stale reviewers from recorded payloads
+func TestReassignService_HandlePullRequestEvent(t *testing.T) {
+	const head = "2222222222222222222222222222222222222222"
+
+	tests := []struct {
+		name              string
+		payload           string
+		reviews           []models.Review
+		expectedReviewers []string
+		expectedErr       error
+	}{
+		{
+			name:    "stale reviewers are re-requested",
+			payload: "pull_request_synchronize.json",
+			reviews: []models.Review{
+				{User: models.User{Login: "alice"}, State: "APPROVED", CommitID: "1111111111111111111111111111111111111111"},
+				{User: models.User{Login: "bob"}, State: "CHANGES_REQUESTED", CommitID: head},
+				{User: models.User{Login: "pending-reviewer"}, State: "COMMENTED", CommitID: "1111111111111111111111111111111111111111"},
+			},
+			expectedReviewers: []string{"alice"},
+		},
+		{
+			name:    "everyone reviewed the head commit",
+			payload: "pull_request_synchronize.json",
+			reviews: []models.Review{
+				{User: models.User{Login: "alice"}, State: "APPROVED", CommitID: head},
+			},
+			expectedErr: ErrNothingToRequest,
+		},
+		{
+			name:        "opened events are ignored",
+			payload:     "pull_request_opened.json",
+			expectedErr: ErrIgnoredEvent,
+		},
+		{
+			name:        "draft pull requests are ignored",
+			payload:     "pull_request_synchronize_draft.json",
+			expectedErr: ErrIgnoredEvent,
+		},
+		{
+			name:        "closed pull requests are ignored",
+			payload:     "pull_request_synchronize_closed.json",
+			expectedErr: ErrIgnoredEvent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ci.ReadEventFile(filepath.Join("testdata", tt.payload))
+			if err != nil {
+				t.Fatalf("Failed to read payload: %v", err)
+			}
+
+			client := &github.MockClient{
+				CurrentUserError:    github.NewAPIError("Resource not accessible by integration"),
+				ReviewersCommenters: []string{"alice", "bob", "pending-reviewer"},
+				RequestedReviewers:  []string{"pending-reviewer"},
+				HeadSHA:             head,
+				Reviews:             tt.reviews,
+				CompareSummaries: map[string]*models.CompareSummary{
+					"1111111111111111111111111111111111111111": {Commits: 1, FilesChanged: 1},
+				},
+			}
+			repo := &github.Repository{Owner: "octo-org", Name: "octo-repo"}
+			prompter := &ui.MockPrompter{}
+			service := NewReassignService(client, repo, prompter)
+
+			plan, err := service.HandlePullRequestEvent(context.Background(), event)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("HandlePullRequestEvent() error = %v, want %v", err, tt.expectedErr)
+			}
+			if prompter.SelectReviewerCalled || prompter.ConfirmSelectionCalled {
+				t.Errorf("Events must never prompt")
+			}
+			if client.GetCurrentUserLoginCalled {
+				t.Errorf("The PR author should be used instead of the token's user")
+			}
+			if tt.expectedErr != nil {
+				if client.ReassignReviewersCalled {
+					t.Errorf("Expected no review request")
+				}
+				return
+			}
+
+			if strings.Join(plan.Reviewers, ",") != strings.Join(tt.expectedReviewers, ",") {
+				t.Errorf("Expected reviewers %v, got %v", tt.expectedReviewers, plan.Reviewers)
+			}
+			if client.LastPRNumber != 42 || client.LastOwner != "octo-org" || client.LastRepo != "octo-repo" {
+				t.Errorf("Unexpected request target %s/%s#%d", client.LastOwner, client.LastRepo, client.LastPRNumber)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WithGroups defines reviewer groups that --reviewer can name as "@group"
+func WithGroups(groups map[string][]string) Option {
+	return func(s *ReassignService) {
+		s.groups = groups
+	}
+}
+
+// WithIncludeNew expands "@group" to every member rather than only those who took part in the PR
+func WithIncludeNew(include bool) Option {
+	return func(s *ReassignService) {
+		s.includeNew = include
+	}
+}
+
+// expandReviewers replaces each "@group" in the requested reviewers with its members.
+// Unless WithIncludeNew is set, only members who already reviewed or commented on the PR are kept.
+func (s *ReassignService) expandReviewers(ctx context.Context, prNumber int, self string) ([]string, error) {
+	var participants map[string]bool
+	took := func(login string) (bool, error) {
+		if s.includeNew {
+			return true, nil
+		}
+		if participants == nil {
+			logins, err := s.client.GetReviewersAndCommenters(ctx, s.repo.GetOwner(), s.repo.GetName(), prNumber, self)
+			if err != nil {
+				return false, fmt.Errorf("failed to get reviewers and commenters: %w", err)
+			}
+			participants = make(map[string]bool, len(logins))
+			for _, login := range logins {
+				participants[strings.ToLower(login)] = true
+			}
+		}
+		return participants[strings.ToLower(login)], nil
+	}
+
+	expanded, groupNames, err := s.expandGroups(s.reviewers, self, took)
+	if err != nil {
+		return nil, err
+	}
+	if len(expanded) == 0 {
+		return nil, fmt.Errorf("%w: no member of %s took part in the PR; pass --include-new to request them anyway",
+			ErrNothingToRequest, strings.Join(groupNames, ", "))
+	}
+	return expanded, nil
+}
+
+// expandGroups replaces each "@group" in names with its members other than self for whom keep is true,
+// dropping duplicates. It also returns the group names it expanded.
+func (s *ReassignService) expandGroups(names []string, self string, keep func(login string) (bool, error)) ([]string, []string, error) {
+	var expanded, groupNames []string
+	seen := make(map[string]bool)
+	add := func(login string) {
+		if !seen[strings.ToLower(login)] {
+			seen[strings.ToLower(login)] = true
+			expanded = append(expanded, login)
+		}
+	}
+
+	for _, name := range names {
+		group, isGroup := strings.CutPrefix(name, "@")
+		if !isGroup {
+			add(name)
+			continue
+		}
+		members, ok := s.group(group)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown reviewer group %q", name)
+		}
+		groupNames = append(groupNames, name)
+
+		for _, member := range members {
+			if strings.EqualFold(member, self) {
+				continue
+			}
+			kept, err := keep(member)
+			if err != nil {
+				return nil, nil, err
+			}
+			if kept {
+				add(member)
+			}
+		}
+	}
+	return expanded, groupNames, nil
+}
+
+// group returns the members of the named group, matching the name case-insensitively
+func (s *ReassignService) group(name string) ([]string, bool) {
+	for group, members := range s.groups {
+		if strings.EqualFold(group, name) {
+			return members, true
+		}
+	}
+	return nil, false
+}
@@ -1,11 +1,20 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+	"golang.org/x/sync/errgroup"
 )
 
 // ReassignService contains the business logic
@@ -13,85 +22,369 @@ type ReassignService struct {
 	client   github.GitHubClient
 	repo     github.RepositoryInfo
 	prompter ui.Prompter
+
+	autoConfirm bool
+	reviewers   []string
+	strategy    Strategy
+	self        string
+	selfKnown   bool // self was given up front, possibly empty
+
+	cooldown    time.Duration
+	force       bool
+	includeBusy bool
+
+	availability   models.Availability
+	backups        map[string][]string
+	handoffComment bool
+	groups         map[string][]string
+	includeNew     bool
+	org            string
+	allRepos       bool
+
+	audit     audit.Recorder
+	auditMode audit.Mode
+	now       func() time.Time
+}
+
+// Strategy decides which reviewers to re-request without asking the user
+type Strategy string
+
+const (
+	// StrategyPrompt asks the user to select a reviewer
+	StrategyPrompt Strategy = ""
+	// StrategyAll re-requests every previous reviewer and commenter
+	StrategyAll Strategy = "all"
+	// StrategyStale re-requests reviewers whose last review predates the current head and who are not already requested
+	StrategyStale Strategy = "stale"
+	// StrategySubstitute hands the requests of unavailable reviewers, or of WithReviewers, over to their backups
+	StrategySubstitute Strategy = "substitute"
+)
+
+// ErrNothingToRequest is returned when the strategy selects no reviewers
+var ErrNothingToRequest = errors.New("no reviewers need to be re-requested")
+
+// ErrAuditFailed is returned when GitHub accepted a change but the audit log could not record it
+var ErrAuditFailed = errors.New("failed to record audit entry")
+
+// ParseStrategy validates a strategy name given on the command line
+func ParseStrategy(name string) (Strategy, error) {
+	switch strategy := Strategy(name); strategy {
+	case StrategyPrompt, StrategyAll, StrategyStale, StrategySubstitute:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown strategy %q: expected %q, %q or %q", name, StrategyAll, StrategyStale, StrategySubstitute)
+	}
+}
+
+// Option configures optional behavior of ReassignService
+type Option func(*ReassignService)
+
+// WithAutoConfirm skips the confirmation prompt
+func WithAutoConfirm(autoConfirm bool) Option {
+	return func(s *ReassignService) {
+		s.autoConfirm = autoConfirm
+	}
+}
+
+// WithReviewers re-requests the given reviewers instead of selecting them
+func WithReviewers(reviewers []string) Option {
+	return func(s *ReassignService) {
+		s.reviewers = reviewers
+	}
+}
+
+// WithStrategy selects reviewers automatically instead of prompting
+func WithStrategy(strategy Strategy) Option {
+	return func(s *ReassignService) {
+		s.strategy = strategy
+	}
+}
+
+// WithSelf uses login as the current user instead of asking the API,
+// which is needed for tokens that cannot read their own user such as GITHUB_TOKEN.
+// An empty login excludes nobody from the reviewers.
+func WithSelf(login string) Option {
+	return func(s *ReassignService) {
+		s.self = login
+		s.selfKnown = true
+	}
+}
+
+// WithCooldown refuses to re-request a reviewer who was requested on the same PR within d
+func WithCooldown(d time.Duration) Option {
+	return func(s *ReassignService) {
+		s.cooldown = d
+	}
+}
+
+// WithForce re-requests reviewers even within the cooldown
+func WithForce(force bool) Option {
+	return func(s *ReassignService) {
+		s.force = force
+	}
+}
+
+// WithIncludeBusy lets strategies select reviewers whose GitHub status is marked busy
+func WithIncludeBusy(include bool) Option {
+	return func(s *ReassignService) {
+		s.includeBusy = include
+	}
+}
+
+// WithOrg lists the assigned PRs of every repository in org instead of the current repository
+func WithOrg(org string) Option {
+	return func(s *ReassignService) {
+		s.org = org
+	}
+}
+
+// WithAllRepos lists the assigned PRs of every repository the user can see
+func WithAllRepos(all bool) Option {
+	return func(s *ReassignService) {
+		s.allRepos = all
+	}
+}
+
+// WithAvailability hides absent reviewers from strategies and warns before requesting them
+func WithAvailability(availability models.Availability) Option {
+	return func(s *ReassignService) {
+		s.availability = availability
+	}
+}
+
+// WithBackups maps each reviewer to the reviewers who stand in for them while unavailable
+func WithBackups(backups map[string][]string) Option {
+	return func(s *ReassignService) {
+		s.backups = backups
+	}
+}
+
+// WithAuditLog records every review request sent, successful or not
+func WithAuditLog(recorder audit.Recorder) Option {
+	return func(s *ReassignService) {
+		s.audit = recorder
+	}
+}
+
+// WithAuditMode overrides the mode recorded in the audit log, e.g. for CI runs
+func WithAuditMode(mode audit.Mode) Option {
+	return func(s *ReassignService) {
+		s.auditMode = mode
+	}
 }
 
 // NewReassignService creates a new service instance
-func NewReassignService(client github.GitHubClient, repo github.RepositoryInfo, prompter ui.Prompter) *ReassignService {
-	return &ReassignService{
+func NewReassignService(client github.GitHubClient, repo github.RepositoryInfo, prompter ui.Prompter, opts ...Option) *ReassignService {
+	s := &ReassignService{
 		client:   client,
 		repo:     repo,
 		prompter: prompter,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// ProcessReassignment handles the complete workflow
-func (s *ReassignService) ProcessReassignment(args []string) error {
+// ProcessReassignment handles the complete workflow and returns the requests that were sent
+func (s *ReassignService) ProcessReassignment(ctx context.Context, args []string) (*models.ReassignPlan, error) {
 	// Get current user
-	self, err := s.client.GetCurrentUserLogin()
+	self, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get PR from args or prompt
+	ref, err := s.resolvePR(ctx, args, self)
 	if err != nil {
-		return fmt.Errorf("failed to get current user: %w", err)
+		return nil, fmt.Errorf("failed to get PR number: %w", err)
 	}
+	prNumber := ref.Number
 
-	// Get PR number from args or prompt
-	prNumber, err := s.getPRNumber(args, self)
+	// A PR URL may point at another repository on the same host
+	s = s.ForRepository(ref.Owner, ref.Repo)
+
+	if s.strategy == StrategySubstitute {
+		return s.Substitute(ctx, prNumber, s.reviewers, self)
+	}
+
+	// Select reviewers
+	selectedReviewers, notes, err := s.selectReviewers(ctx, prNumber, self)
 	if err != nil {
-		return fmt.Errorf("failed to get PR number: %w", err)
+		return nil, err
+	}
+	return s.reassign(ctx, prNumber, selectedReviewers, notes, self)
+}
+
+// selectReviewers picks the reviewers to re-request from the options, the strategy or the prompter.
+// Strategies also return notes on the unavailable reviewers they replaced or skipped.
+func (s *ReassignService) selectReviewers(ctx context.Context, prNumber int, self string) ([]string, []string, error) {
+	if len(s.reviewers) > 0 {
+		reviewers, err := s.expandReviewers(ctx, prNumber, self)
+		return reviewers, nil, err
 	}
 
 	// Get available reviewers
-	reviewers, err := s.client.GetReviewersAndCommenters(s.repo.GetOwner(), s.repo.GetName(), prNumber, self)
+	reviewers, err := s.client.GetReviewersAndCommenters(ctx, s.repo.GetOwner(), s.repo.GetName(), prNumber, self)
 	if err != nil {
-		return fmt.Errorf("failed to get reviewers and commenters: %w", err)
+		return nil, nil, fmt.Errorf("failed to get reviewers and commenters: %w", err)
 	}
 
 	if len(reviewers) == 0 {
-		return fmt.Errorf("no available reviewers to re-request")
+		return nil, nil, fmt.Errorf("no available reviewers to re-request")
+	}
+
+	if s.strategy == StrategyAll {
+		return s.pickAvailable(ctx, reviewers, self, s.userStatuses(ctx, reviewers))
+	}
+
+	// Summarize what changed since each reviewer's last review
+	candidates, err := s.GetReviewerCandidates(ctx, prNumber, reviewers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get changes since last review: %w", err)
+	}
+
+	if s.strategy == StrategyStale {
+		stale, err := s.requireStale(ctx, prNumber, candidates)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s.pickAvailable(ctx, stale, self, statusesOf(candidates))
 	}
 
 	// Select reviewer
-	selectedReviewer, err := s.prompter.SelectReviewer(reviewers)
+	selectedReviewer, err := s.prompter.SelectReviewer(ctx, candidates)
 	if err != nil {
-		return fmt.Errorf("failed to select reviewer: %w", err)
+		return nil, nil, fmt.Errorf("failed to select reviewer: %w", err)
 	}
+	return []string{selectedReviewer}, nil, nil
+}
 
-	// Confirm selection
-	confirmed, err := s.prompter.ConfirmSelection(selectedReviewer)
+// requireStale returns the stale reviewers, or ErrNothingToRequest when there are none
+func (s *ReassignService) requireStale(ctx context.Context, prNumber int, candidates []models.ReviewerCandidate) ([]string, error) {
+	stale, err := s.StaleReviewers(ctx, prNumber, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(stale) == 0 {
+		return nil, ErrNothingToRequest
+	}
+	return stale, nil
+}
+
+// StaleReviewers returns the candidates who reviewed an older commit and are not already requested
+func (s *ReassignService) StaleReviewers(ctx context.Context, prNumber int, candidates []models.ReviewerCandidate) ([]string, error) {
+	requested, err := s.client.GetRequestedReviewers(ctx, s.repo.GetOwner(), s.repo.GetName(), prNumber)
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string]bool, len(requested))
+	for _, login := range requested {
+		pending[login] = true
+	}
+
+	var stale []string
+	for _, candidate := range candidates {
+		if candidate.LastReviewedCommit == "" || pending[candidate.Login] {
+			continue
+		}
+		// A review whose commit can no longer be compared predates a force push
+		if candidate.Changes == nil || candidate.Changes.Commits > 0 {
+			stale = append(stale, candidate.Login)
+		}
+	}
+	return stale, nil
+}
+
+// currentUser returns the login excluded from the reviewers
+func (s *ReassignService) currentUser(ctx context.Context) (string, error) {
+	if s.selfKnown {
+		return s.self, nil
+	}
+
+	self, err := s.client.GetCurrentUserLogin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	return self, nil
+}
+
+// confirm asks the user to confirm the plan unless auto-confirm is enabled
+func (s *ReassignService) confirm(ctx context.Context, plan models.ReassignPlan) error {
+	if s.autoConfirm {
+		return nil
+	}
+
+	confirmed, err := s.prompter.ConfirmSelection(ctx, plan)
 	if err != nil {
 		return fmt.Errorf("failed to confirm selection: %w", err)
 	}
 	if !confirmed {
 		return fmt.Errorf("reviewer selection cancelled")
 	}
+	return nil
+}
+
+// resolvePR accepts a PR URL from any GitHub host in place of the PR number
+func (s *ReassignService) resolvePR(ctx context.Context, args []string, self string) (models.PRRef, error) {
+	if len(args) >= 2 && strings.Contains(args[1], "://") {
+		_, ref, err := github.ParsePullRequestURL(args[1])
+		return ref, err
+	}
+	if len(args) < 2 {
+		return s.selectPR(ctx, self)
+	}
 
-	// Reassign reviewer
-	err = s.client.ReassignReviewers(s.repo.GetOwner(), s.repo.GetName(), prNumber, []string{selectedReviewer})
+	if s.repo.GetOwner() == "" {
+		return models.PRRef{}, fmt.Errorf("a PR number needs a repository; run inside one or pass the PR URL")
+	}
+	prNumber, err := parsePRNumber(args[1])
 	if err != nil {
-		return fmt.Errorf("failed to reassign reviewers: %w", err)
+		return models.PRRef{}, err
 	}
+	return models.PRRef{Owner: s.repo.GetOwner(), Repo: s.repo.GetName(), Number: prNumber}, nil
+}
 
-	return nil
+// parsePRNumber reads a PR number argument
+func parsePRNumber(arg string) (int, error) {
+	prNumber, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid PR number: %w", err)
+	}
+	if prNumber <= 0 {
+		return 0, fmt.Errorf("PR number must be positive")
+	}
+	return prNumber, nil
 }
 
-// getPRNumber gets PR number from args or prompts user
-func (s *ReassignService) getPRNumber(args []string, self string) (int, error) {
-	if len(args) >= 2 {
-		prNumber, err := strconv.Atoi(args[1])
-		if err != nil {
-			return 0, fmt.Errorf("invalid PR number: %w", err)
-		}
-		if prNumber <= 0 {
-			return 0, fmt.Errorf("PR number must be positive")
-		}
-		return prNumber, nil
+// selectPR prompts for one of the PRs assigned to self within the PR scope
+func (s *ReassignService) selectPR(ctx context.Context, self string) (models.PRRef, error) {
+	prs, err := s.client.GetAssignedPRs(ctx, s.prScope(), self)
+	if err != nil {
+		return models.PRRef{}, fmt.Errorf("failed to get assigned PRs: %w", err)
 	}
 
-	// No PR number provided, prompt user
-	prs, err := s.client.GetAssignedPRs(s.repo.GetOwner(), s.repo.GetName(), self)
+	pr, err := s.prompter.SelectPR(ctx, prs)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get assigned PRs: %w", err)
+		return models.PRRef{}, err
 	}
+	ref := models.PRRef{Owner: pr.Owner, Repo: pr.Repo, Number: pr.Number}
+	if ref.Owner == "" {
+		ref.Owner, ref.Repo = s.repo.GetOwner(), s.repo.GetName()
+	}
+	return ref, nil
+}
 
-	return s.prompter.SelectPR(prs)
+// prScope is the search qualifier limiting which assigned PRs are listed
+func (s *ReassignService) prScope() string {
+	switch {
+	case s.allRepos:
+		return ""
+	case s.org != "":
+		return "org:" + s.org
+	default:
+		return fmt.Sprintf("repo:%s/%s", s.repo.GetOwner(), s.repo.GetName())
+	}
 }
 
 // ValidateReviewers checks if reviewers list is valid
@@ -113,8 +406,8 @@ func (s *ReassignService) ValidateReviewers(reviewers []string, self string) err
 }
 
 // GetAvailableReviewers returns filtered list of available reviewers
-func (s *ReassignService) GetAvailableReviewers(prNumber int, self string) ([]string, error) {
-	reviewers, err := s.client.GetReviewersAndCommenters(s.repo.GetOwner(), s.repo.GetName(), prNumber, self)
+func (s *ReassignService) GetAvailableReviewers(ctx context.Context, prNumber int, self string) ([]string, error) {
+	reviewers, err := s.client.GetReviewersAndCommenters(ctx, s.repo.GetOwner(), s.repo.GetName(), prNumber, self)
 	if err != nil {
 		return nil, err
 	}
@@ -129,3 +422,308 @@ func (s *ReassignService) GetAvailableReviewers(prNumber int, self string) ([]st
 
 	return validReviewers, nil
 }
+
+// GetReviewerCandidates attaches to each reviewer the changes made since their last reviewed commit
+func (s *ReassignService) GetReviewerCandidates(ctx context.Context, prNumber int, reviewers []string) ([]models.ReviewerCandidate, error) {
+	owner, name := s.repo.GetOwner(), s.repo.GetName()
+
+	var head string
+	var reviews []models.Review
+	var userStatuses map[string]models.UserStatus
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		head, err = s.client.GetPullRequestHeadSHA(gctx, owner, name, prNumber)
+		return err
+	})
+	g.Go(func() error {
+		userStatuses = s.userStatuses(gctx, reviewers)
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		reviews, err = s.client.GetReviews(gctx, owner, name, prNumber)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	// Reviews are returned in chronological order, so the last one wins
+	lastReviewed := make(map[string]string)
+	for _, review := range reviews {
+		if review.CommitID != "" {
+			lastReviewed[review.User.Login] = review.CommitID
+		}
+	}
+
+	summaries, err := s.compareSinceReview(ctx, owner, name, head, reviewers, lastReviewed)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]models.ReviewerCandidate, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		candidate := models.ReviewerCandidate{
+			Login:   reviewer,
+			Status:  statusOf(userStatuses, reviewer),
+			Absence: s.absence(reviewer),
+		}
+		if base, ok := lastReviewed[reviewer]; ok {
+			candidate.LastReviewedCommit = base
+			candidate.Changes = summaries[base]
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// compareSinceReview compares each distinct reviewed commit with head concurrently, keyed by base commit.
+// A reviewed commit that no longer exists, e.g. after a force push, has a nil summary; other errors are returned.
+func (s *ReassignService) compareSinceReview(ctx context.Context, owner, name, head string, reviewers []string, lastReviewed map[string]string) (map[string]*models.CompareSummary, error) {
+	var mu sync.Mutex
+	g, gctx := errgroup.WithContext(ctx)
+	summaries := make(map[string]*models.CompareSummary)
+	compared := make(map[string]bool)
+
+	for _, reviewer := range reviewers {
+		base, ok := lastReviewed[reviewer]
+		// Several reviewers often share the same base commit
+		if !ok || compared[base] {
+			continue
+		}
+		compared[base] = true
+
+		g.Go(func() error {
+			summary := &models.CompareSummary{BaseCommit: base, HeadCommit: head}
+			if base != head {
+				var err error
+				summary, err = s.client.CompareCommits(gctx, owner, name, base, head)
+				if errors.Is(err, github.ErrCommitNotFound) {
+					summary = nil
+				} else if err != nil {
+					return err
+				}
+			}
+			mu.Lock()
+			summaries[base] = summary
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// ListAssignedPRs returns the open PRs assigned to the current user within the PR scope
+func (s *ReassignService) ListAssignedPRs(ctx context.Context) ([]models.PullRequestInfo, string, error) {
+	self, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prs, err := s.client.GetAssignedPRs(ctx, s.prScope(), self)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get assigned PRs: %w", err)
+	}
+	return prs, self, nil
+}
+
+// GetReviewerStatuses returns the review state of every reviewer, commenter and requested reviewer of the PR
+func (s *ReassignService) GetReviewerStatuses(ctx context.Context, prNumber int, self string) ([]models.ReviewerStatus, error) {
+	owner, name := s.repo.GetOwner(), s.repo.GetName()
+
+	var reviewers, requested []string
+	var reviews []models.Review
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		reviewers, err = s.GetAvailableReviewers(gctx, prNumber, self)
+		if err != nil {
+			return fmt.Errorf("failed to get reviewers and commenters: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		requested, err = s.client.GetRequestedReviewers(gctx, owner, name, prNumber)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		reviews, err = s.client.GetReviews(gctx, owner, name, prNumber)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[string]*models.ReviewerStatus)
+	order := make([]string, 0, len(reviewers)+len(requested))
+	status := func(login string) *models.ReviewerStatus {
+		if st, ok := statuses[login]; ok {
+			return st
+		}
+		statuses[login] = &models.ReviewerStatus{Login: login}
+		order = append(order, login)
+		return statuses[login]
+	}
+
+	for _, reviewer := range reviewers {
+		status(reviewer)
+	}
+	for _, login := range requested {
+		if login != self {
+			status(login).Pending = true
+		}
+	}
+	// Reviews are returned in chronological order, so the last one wins
+	for _, review := range reviews {
+		st, ok := statuses[review.User.Login]
+		if !ok {
+			continue
+		}
+		st.State = review.State
+		if review.SubmittedAt != "" {
+			st.LastActivity = review.SubmittedAt
+		}
+	}
+
+	sort.Strings(order)
+	userStatuses := s.userStatuses(ctx, order)
+	result := make([]models.ReviewerStatus, 0, len(order))
+	for _, login := range order {
+		statuses[login].Status = statusOf(userStatuses, login)
+		statuses[login].Absence = s.absence(login)
+		result = append(result, *statuses[login])
+	}
+	return result, nil
+}
+
+// Reassign re-requests reviewers picked elsewhere, e.g. in the TUI, the way ProcessReassignment
+// does: it validates them, applies the cooldown and, unless auto-confirmed, warns about unavailable
+// reviewers and asks for confirmation. The returned plan carries the warnings.
+func (s *ReassignService) Reassign(ctx context.Context, prNumber int, reviewers []string, self string) (*models.ReassignPlan, error) {
+	return s.reassign(ctx, prNumber, reviewers, nil, self)
+}
+
+// reassign confirms and requests the selected reviewers; notes are the warnings of the selection
+func (s *ReassignService) reassign(ctx context.Context, prNumber int, reviewers, notes []string, self string) (*models.ReassignPlan, error) {
+	if err := s.ValidateReviewers(reviewers, self); err != nil {
+		return nil, err
+	}
+	// Checked before confirming so the plan shows who will actually be requested
+	reviewers, skipped, err := s.applyCooldown(ctx, prNumber, reviewers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Confirm selection
+	plan := &models.ReassignPlan{
+		Owner:     s.repo.GetOwner(),
+		Repo:      s.repo.GetName(),
+		PRNumber:  prNumber,
+		Reviewers: reviewers,
+		Warnings:  append(notes, skipped...),
+	}
+	if !s.autoConfirm {
+		plan.Warnings = append(plan.Warnings, s.availabilityWarnings(ctx, reviewers, self)...)
+	}
+	if err := s.confirm(ctx, *plan); err != nil {
+		return nil, err
+	}
+
+	// Reassign reviewer
+	if err := s.request(ctx, plan.PRNumber, plan.Reviewers, self); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// request sends the review request and records it in the audit log
+func (s *ReassignService) request(ctx context.Context, prNumber int, reviewers []string, self string) error {
+	added, err := s.newlyRequested(ctx, prNumber, reviewers)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.ReassignReviewers(ctx, s.repo.GetOwner(), s.repo.GetName(), prNumber, reviewers)
+	auditErr := s.record(audit.Entry{Action: audit.ActionRequest, Actor: self, PR: prNumber, Reviewers: reviewers, Added: added}, err)
+	if err != nil {
+		return fmt.Errorf("failed to reassign reviewers: %w", err)
+	}
+	if auditErr != nil {
+		return fmt.Errorf("review requested but %w", auditErr)
+	}
+	return nil
+}
+
+// newlyRequested returns the reviewers that are not pending yet, so that undo leaves the others requested.
+// The lookup is skipped when there is no audit log to record it in.
+func (s *ReassignService) newlyRequested(ctx context.Context, prNumber int, reviewers []string) ([]string, error) {
+	if s.audit == nil {
+		return nil, nil
+	}
+
+	requested, err := s.client.GetRequestedReviewers(ctx, s.repo.GetOwner(), s.repo.GetName(), prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch requested reviewers: %w", err)
+	}
+	pending := make(map[string]bool, len(requested))
+	for _, login := range requested {
+		pending[strings.ToLower(login)] = true
+	}
+
+	added := []string{}
+	for _, reviewer := range reviewers {
+		if !pending[strings.ToLower(reviewer)] {
+			added = append(added, reviewer)
+		}
+	}
+	return added, nil
+}
+
+// record writes the outcome of a change to the review requests to the audit log, if one is configured.
+// The time, host, repository, mode and outcome of entry are filled in.
+func (s *ReassignService) record(entry audit.Entry, requestErr error) error {
+	if s.audit == nil {
+		return nil
+	}
+
+	entry.Time = s.clock().UTC()
+	entry.Host = s.client.Host()
+	entry.Repo = s.repo.GetOwner() + "/" + s.repo.GetName()
+	entry.Mode = s.mode()
+	entry.Outcome = audit.OutcomeSuccess
+	if requestErr != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = requestErr.Error()
+	}
+	if err := s.audit.Record(entry); err != nil {
+		return fmt.Errorf("%w: %w", ErrAuditFailed, err)
+	}
+	return nil
+}
+
+// mode tells whether the user or a strategy picked the reviewers
+func (s *ReassignService) mode() audit.Mode {
+	switch {
+	case s.auditMode != "":
+		return s.auditMode
+	case s.strategy != StrategyPrompt, len(s.reviewers) > 0 && s.autoConfirm:
+		return audit.ModeAuto
+	default:
+		return audit.ModeInteractive
+	}
+}
+
+func (s *ReassignService) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
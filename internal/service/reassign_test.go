@@ -1,15 +1,22 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
 )
 
-// TestGetPRNumber tests PR number extraction from arguments
-func TestReassignService_getPRNumber(t *testing.T) {
+// TestResolvePR tests PR number extraction from arguments, or prompting without one
+func TestReassignService_resolvePR(t *testing.T) {
 	tests := []struct {
 		name          string
 		args          []string
@@ -75,7 +82,7 @@ func TestReassignService_getPRNumber(t *testing.T) {
 			service := NewReassignService(client, repo, prompter)
 
 			// Test the method
-			prNumber, err := service.getPRNumber(tt.args, "testuser")
+			ref, err := service.resolvePR(context.Background(), tt.args, "testuser")
 
 			// Check error expectation
 			if tt.expectError && err == nil {
@@ -91,8 +98,8 @@ func TestReassignService_getPRNumber(t *testing.T) {
 			}
 
 			// Check result
-			if !tt.expectError && prNumber != tt.expectedPR {
-				t.Errorf("Expected PR number %d, got %d", tt.expectedPR, prNumber)
+			if !tt.expectError && ref.Number != tt.expectedPR {
+				t.Errorf("Expected PR number %d, got %d", tt.expectedPR, ref.Number)
 			}
 		})
 	}
@@ -209,7 +216,7 @@ func TestReassignService_GetAvailableReviewers(t *testing.T) {
 			prompter := &ui.MockPrompter{}
 			service := NewReassignService(client, repo, prompter)
 
-			reviewers, err := service.GetAvailableReviewers(tt.prNumber, tt.self)
+			reviewers, err := service.GetAvailableReviewers(context.Background(), tt.prNumber, tt.self)
 
 			if tt.expectError && err == nil {
 				t.Errorf("Expected error but got none")
@@ -241,6 +248,380 @@ func TestReassignService_GetAvailableReviewers(t *testing.T) {
 	}
 }
 
+// TestGetReviewerCandidates tests the changes summary since each reviewer's last review
+func TestReassignService_GetReviewerCandidates(t *testing.T) {
+	client := &github.MockClient{
+		HeadSHA: "head",
+		Reviews: []models.Review{
+			{User: models.User{Login: "user1"}, CommitID: "old"},
+			{User: models.User{Login: "user2"}, CommitID: "old"},
+			{User: models.User{Login: "user1"}, CommitID: "mid"},
+			{User: models.User{Login: "user3"}, CommitID: "head"},
+			{User: models.User{Login: "user4"}, CommitID: "gone"},
+		},
+		CompareSummaries: map[string]*models.CompareSummary{
+			"old": {BaseCommit: "old", HeadCommit: "head", Commits: 4, FilesChanged: 6, Additions: 50, Deletions: 10},
+			"mid": {BaseCommit: "mid", HeadCommit: "head", Commits: 1, FilesChanged: 2, Additions: 5, Deletions: 1},
+		},
+	}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{})
+
+	candidates, err := service.GetReviewerCandidates(context.Background(), 123, []string{"user1", "user2", "user3", "user4", "commenter"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := map[string]struct {
+		commit  string
+		commits int // -1 means no summary
+	}{
+		"user1":     {commit: "mid", commits: 1},
+		"user2":     {commit: "old", commits: 4},
+		"user3":     {commit: "head", commits: 0},
+		"user4":     {commit: "gone", commits: -1},
+		"commenter": {commit: "", commits: -1},
+	}
+
+	if len(candidates) != len(expected) {
+		t.Fatalf("Expected %d candidates, got %d", len(expected), len(candidates))
+	}
+	for _, candidate := range candidates {
+		want := expected[candidate.Login]
+		if candidate.LastReviewedCommit != want.commit {
+			t.Errorf("%s: expected last reviewed commit %q, got %q", candidate.Login, want.commit, candidate.LastReviewedCommit)
+		}
+		switch {
+		case want.commits < 0 && candidate.Changes != nil:
+			t.Errorf("%s: expected no changes summary, got %+v", candidate.Login, candidate.Changes)
+		case want.commits >= 0 && candidate.Changes == nil:
+			t.Errorf("%s: expected changes summary, got nil", candidate.Login)
+		case want.commits >= 0 && candidate.Changes.Commits != want.commits:
+			t.Errorf("%s: expected %d commits, got %d", candidate.Login, want.commits, candidate.Changes.Commits)
+		}
+	}
+
+	// "old" is shared by two reviewers and "head" needs no comparison
+	if client.CompareCommitsCalls != 3 {
+		t.Errorf("Expected 3 compare calls, got %d", client.CompareCommitsCalls)
+	}
+}
+
+// TestReassignService_GetReviewerCandidatesCompareError tests that only a missing commit is tolerated
+func TestReassignService_GetReviewerCandidatesCompareError(t *testing.T) {
+	client := &github.MockClient{
+		HeadSHA:      "head",
+		Reviews:      []models.Review{{User: models.User{Login: "user1"}, CommitID: "old"}},
+		CompareError: github.NewNetworkError(),
+	}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{})
+
+	if _, err := service.GetReviewerCandidates(context.Background(), 123, []string{"user1"}); err == nil {
+		t.Error("Expected a network error to be returned instead of being reported as a force push")
+	}
+}
+
+// TestGetReviewerCandidatesError tests error propagation when the PR cannot be fetched
+func TestReassignService_GetReviewerCandidatesError(t *testing.T) {
+	client := &github.MockClient{HeadSHAError: github.NewAPIError("not found")}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{})
+
+	if _, err := service.GetReviewerCandidates(context.Background(), 123, []string{"user1"}); err == nil {
+		t.Errorf("Expected error but got none")
+	}
+	// Reviews are fetched alongside the PR, but nothing is compared once it fails
+	if client.CompareCommitsCalls != 0 {
+		t.Errorf("Expected no compare calls, got %d", client.CompareCommitsCalls)
+	}
+}
+
+// TestGetReviewerStatuses tests merging reviewers, requested reviewers and review states
+func TestReassignService_GetReviewerStatuses(t *testing.T) {
+	client := &github.MockClient{
+		ReviewersCommenters: []string{"user2", "user1"},
+		RequestedReviewers:  []string{"user3", "user1"},
+		Reviews: []models.Review{
+			{User: models.User{Login: "user1"}, State: "COMMENTED", SubmittedAt: "2023-01-01T10:00:00Z"},
+			{User: models.User{Login: "user1"}, State: "APPROVED", SubmittedAt: "2023-01-02T10:00:00Z"},
+			{User: models.User{Login: "user2"}, State: "CHANGES_REQUESTED", SubmittedAt: "2023-01-03T10:00:00Z"},
+		},
+	}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{})
+
+	statuses, err := service.GetReviewerStatuses(context.Background(), 123, "currentuser")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []models.ReviewerStatus{
+		{Login: "user1", State: "APPROVED", Pending: true, LastActivity: "2023-01-02T10:00:00Z"},
+		{Login: "user2", State: "CHANGES_REQUESTED", LastActivity: "2023-01-03T10:00:00Z"},
+		{Login: "user3", Pending: true},
+	}
+	if len(statuses) != len(expected) {
+		t.Fatalf("Expected %d statuses, got %d: %+v", len(expected), len(statuses), statuses)
+	}
+	for i := range expected {
+		if statuses[i] != expected[i] {
+			t.Errorf("Expected status %+v, got %+v", expected[i], statuses[i])
+		}
+	}
+}
+
+// TestReassign tests validation before re-requesting reviews
+func TestReassignService_Reassign(t *testing.T) {
+	client := &github.MockClient{}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{ConfirmedSelection: true})
+
+	if _, err := service.Reassign(context.Background(), 123, []string{"currentuser"}, "currentuser"); err == nil {
+		t.Errorf("Expected error when reassigning self")
+	}
+	if client.ReassignReviewersCalled {
+		t.Errorf("Reviewers should not be reassigned when validation fails")
+	}
+
+	if _, err := service.Reassign(context.Background(), 123, []string{"user1", "user2"}, "currentuser"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.LastPRNumber != 123 || len(client.LastReviewers) != 2 {
+		t.Errorf("Expected reviewers to be requested on PR #123, got #%d %v", client.LastPRNumber, client.LastReviewers)
+	}
+}
+
+// TestReassignAuditLog tests that every review request is recorded with its mode and outcome
+func TestReassignService_ReassignAuditLog(t *testing.T) {
+	// user3 was already requested, so only user1 is added
+	client := &github.MockClient{APIHost: "ghe.example.com", RequestedReviewers: []string{"user3"}}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	log := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	interactive := NewReassignService(client, repo, &ui.MockPrompter{ConfirmedSelection: true}, WithAuditLog(log))
+	interactive.now = func() time.Time { return now }
+	if _, err := interactive.Reassign(context.Background(), 1, []string{"user1", "user3"}, "currentuser"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	client.ReassignError = github.NewAPIError("validation failed")
+	auto := NewReassignService(client, repo, &ui.MockPrompter{ConfirmedSelection: true}, WithAuditLog(log), WithStrategy(StrategyStale))
+	if _, err := auto.Reassign(context.Background(), 2, []string{"user2"}, "currentuser"); err == nil {
+		t.Fatalf("Expected error but got none")
+	}
+
+	// Rejected before any request is sent, so nothing is recorded
+	if _, err := auto.Reassign(context.Background(), 3, nil, "currentuser"); err == nil {
+		t.Fatalf("Expected error but got none")
+	}
+
+	entries, err := log.Read(audit.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	first := entries[0]
+	if !first.Time.Equal(now) || first.Actor != "currentuser" || first.Repo != "owner/repo" || first.PR != 1 ||
+		first.Host != "ghe.example.com" || !slices.Equal(first.Added, []string{"user1"}) ||
+		first.Mode != audit.ModeInteractive || first.Outcome != audit.OutcomeSuccess {
+		t.Errorf("Unexpected first entry: %+v", first)
+	}
+	second := entries[1]
+	if second.Mode != audit.ModeAuto || second.Outcome != audit.OutcomeFailure || !containsString(second.Error, "validation failed") {
+		t.Errorf("Unexpected second entry: %+v", second)
+	}
+}
+
+// TestProcessReassignment tests the confirmation step of the complete workflow
+func TestReassignService_ProcessReassignment(t *testing.T) {
+	tests := []struct {
+		name           string
+		autoConfirm    bool
+		confirmed      bool
+		expectConfirm  bool
+		expectReassign bool
+		expectError    bool
+	}{
+		{name: "confirmed", confirmed: true, expectConfirm: true, expectReassign: true},
+		{name: "declined", confirmed: false, expectConfirm: true, expectError: true},
+		{name: "auto confirm skips prompt", autoConfirm: true, expectReassign: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{
+				CurrentUser:         "currentuser",
+				ReviewersCommenters: []string{"user1"},
+				HeadSHA:             "head",
+			}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			prompter := &ui.MockPrompter{SelectedReviewer: "user1", ConfirmedSelection: tt.confirmed}
+			service := NewReassignService(client, repo, prompter, WithAutoConfirm(tt.autoConfirm))
+
+			_, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+			if tt.expectError != (err != nil) {
+				t.Fatalf("Unexpected error result: %v", err)
+			}
+			if prompter.ConfirmSelectionCalled != tt.expectConfirm {
+				t.Errorf("Expected confirmation called = %v", tt.expectConfirm)
+			}
+			if client.ReassignReviewersCalled != tt.expectReassign {
+				t.Errorf("Expected reassign called = %v", tt.expectReassign)
+			}
+			if tt.expectConfirm {
+				plan := prompter.LastPlan
+				if plan.Owner != "owner" || plan.Repo != "repo" || plan.PRNumber != 123 || len(plan.Reviewers) != 1 || plan.Reviewers[0] != "user1" {
+					t.Errorf("Unexpected plan: %+v", plan)
+				}
+			}
+		})
+	}
+}
+
+// TestProcessReassignmentStrategies tests selecting reviewers without prompting
+func TestReassignService_ProcessReassignmentStrategies(t *testing.T) {
+	tests := []struct {
+		name              string
+		opts              []Option
+		expectedReviewers []string
+		expectedErr       error
+	}{
+		{
+			name:              "explicit reviewers",
+			opts:              []Option{WithReviewers([]string{"user9"})},
+			expectedReviewers: []string{"user9"},
+		},
+		{
+			name:              "all reviewers",
+			opts:              []Option{WithStrategy(StrategyAll)},
+			expectedReviewers: []string{"user1", "user2", "user3", "commenter"},
+		},
+		{
+			name:              "stale reviewers",
+			opts:              []Option{WithStrategy(StrategyStale)},
+			expectedReviewers: []string{"user1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{
+				CurrentUser:         "currentuser",
+				ReviewersCommenters: []string{"user1", "user2", "user3", "commenter"},
+				HeadSHA:             "head",
+				Reviews: []models.Review{
+					{User: models.User{Login: "user1"}, CommitID: "old"},
+					{User: models.User{Login: "user2"}, CommitID: "head"},
+					{User: models.User{Login: "user3"}, CommitID: "old"},
+				},
+				RequestedReviewers: []string{"user3"},
+				CompareSummaries: map[string]*models.CompareSummary{
+					"old": {Commits: 2},
+				},
+			}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			prompter := &ui.NonInteractivePrompter{}
+			opts := append([]Option{WithAutoConfirm(true)}, tt.opts...)
+			service := NewReassignService(client, repo, prompter, opts...)
+
+			plan, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Join(plan.Reviewers, ",") != strings.Join(tt.expectedReviewers, ",") {
+				t.Errorf("Expected reviewers %v, got %v", tt.expectedReviewers, plan.Reviewers)
+			}
+			if strings.Join(client.LastReviewers, ",") != strings.Join(tt.expectedReviewers, ",") {
+				t.Errorf("Expected requested reviewers %v, got %v", tt.expectedReviewers, client.LastReviewers)
+			}
+		})
+	}
+}
+
+// TestReassignService_WithEmptySelf tests that an empty self never asks the API for the current user
+func TestReassignService_WithEmptySelf(t *testing.T) {
+	client := &github.MockClient{
+		CurrentUserError:    github.NewAPIError("Resource not accessible by integration"),
+		ReviewersCommenters: []string{"user1"},
+		HeadSHA:             "head",
+		Reviews:             []models.Review{{User: models.User{Login: "user1"}, CommitID: "old"}},
+		CompareSummaries:    map[string]*models.CompareSummary{"old": {Commits: 1}},
+	}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.NonInteractivePrompter{},
+		WithSelf(""), WithStrategy(StrategyStale), WithAutoConfirm(true))
+
+	plan, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.GetCurrentUserLoginCalled {
+		t.Error("Expected the current user not to be fetched")
+	}
+	if strings.Join(plan.Reviewers, ",") != "user1" {
+		t.Errorf("Expected user1 to be re-requested, got %v", plan.Reviewers)
+	}
+}
+
+// TestProcessReassignmentNothingStale tests the sentinel error when no reviewer is stale
+func TestReassignService_ProcessReassignmentNothingStale(t *testing.T) {
+	client := &github.MockClient{
+		CurrentUser:         "currentuser",
+		ReviewersCommenters: []string{"user1"},
+		HeadSHA:             "head",
+		Reviews:             []models.Review{{User: models.User{Login: "user1"}, CommitID: "head"}},
+	}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.NonInteractivePrompter{}, WithStrategy(StrategyStale))
+
+	plan, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+	if !errors.Is(err, ErrNothingToRequest) {
+		t.Fatalf("Expected ErrNothingToRequest, got %v", err)
+	}
+	if plan != nil || client.ReassignReviewersCalled {
+		t.Errorf("Expected no review request")
+	}
+}
+
+// TestProcessReassignmentPRURL tests that a PR URL selects its own repository
+func TestReassignService_ProcessReassignmentPRURL(t *testing.T) {
+	client := &github.MockClient{CurrentUser: "currentuser", ReviewersCommenters: []string{"user1"}}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{}, WithStrategy(StrategyAll), WithAutoConfirm(true))
+
+	plan, err := service.ProcessReassignment(context.Background(), []string{"program", "https://ghe.example.com/team/service/pull/42"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if plan.Owner != "team" || plan.Repo != "service" || plan.PRNumber != 42 {
+		t.Errorf("Expected team/service#42, got %s/%s#%d", plan.Owner, plan.Repo, plan.PRNumber)
+	}
+	if client.LastOwner != "team" || client.LastRepo != "service" {
+		t.Errorf("Expected requests to team/service, got %s/%s", client.LastOwner, client.LastRepo)
+	}
+
+	if _, err := service.ProcessReassignment(context.Background(), []string{"program", "https://github.com/owner/repo/issues/1"}); err == nil {
+		t.Errorf("Expected error for a non-PR URL")
+	}
+}
+
+// TestParseStrategy tests validation of strategy names
+func TestParseStrategy(t *testing.T) {
+	for _, name := range []string{"", "all", "stale"} {
+		if _, err := ParseStrategy(name); err != nil {
+			t.Errorf("ParseStrategy(%q) unexpected error: %v", name, err)
+		}
+	}
+	if _, err := ParseStrategy("random"); err == nil {
+		t.Errorf("Expected error for unknown strategy")
+	}
+}
+
 // Helper function to check if string contains substring
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr ||
@@ -258,3 +639,121 @@ func findInString(s, substr string) bool {
 	}
 	return false
 }
+
+// TestProcessReassignmentGroups tests expanding "@group" reviewers
+func TestReassignService_ProcessReassignmentGroups(t *testing.T) {
+	groups := map[string][]string{"backend": {"alice", "bob", "carol", "currentuser"}}
+
+	tests := []struct {
+		name            string
+		reviewers       []string
+		includeNew      bool
+		expectReviewers []string
+		expectErrText   string
+	}{
+		{
+			name:            "members who took part",
+			reviewers:       []string{"@backend"},
+			expectReviewers: []string{"alice", "carol"},
+		},
+		{
+			name:            "every member",
+			reviewers:       []string{"@Backend", "dave"},
+			includeNew:      true,
+			expectReviewers: []string{"alice", "bob", "carol", "dave"},
+		},
+		{
+			name:            "duplicates are dropped",
+			reviewers:       []string{"carol", "@backend"},
+			expectReviewers: []string{"carol", "alice"},
+		},
+		{
+			name:          "unknown group",
+			reviewers:     []string{"@frontend"},
+			expectErrText: `unknown reviewer group "@frontend"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{
+				CurrentUser:         "currentuser",
+				ReviewersCommenters: []string{"alice", "carol", "erin"},
+			}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			service := NewReassignService(client, repo, &ui.MockPrompter{}, WithAutoConfirm(true),
+				WithReviewers(tt.reviewers), WithGroups(groups), WithIncludeNew(tt.includeNew))
+
+			plan, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+			if tt.expectErrText != "" {
+				if err == nil || err.Error() != tt.expectErrText {
+					t.Fatalf("Expected error %q, got %v", tt.expectErrText, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Join(plan.Reviewers, ",") != strings.Join(tt.expectReviewers, ",") {
+				t.Errorf("Expected reviewers %v, got %v", tt.expectReviewers, plan.Reviewers)
+			}
+			if tt.includeNew && client.GetReviewersAndCommentersCalled {
+				t.Error("Expected participants not to be fetched with --include-new")
+			}
+		})
+	}
+
+	// Nobody from the group took part
+	client := &github.MockClient{CurrentUser: "currentuser", ReviewersCommenters: []string{"erin"}}
+	service := NewReassignService(client, &github.MockRepository{Owner: "owner", Name: "repo"}, &ui.MockPrompter{},
+		WithAutoConfirm(true), WithReviewers([]string{"@backend"}), WithGroups(groups))
+	if _, err := service.ProcessReassignment(context.Background(), []string{"program", "123"}); !errors.Is(err, ErrNothingToRequest) {
+		t.Errorf("Expected ErrNothingToRequest, got %v", err)
+	}
+}
+
+// TestProcessReassignmentAcrossRepositories tests picking a PR from another repository
+func TestReassignService_ProcessReassignmentAcrossRepositories(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		expectScope string
+	}{
+		{name: "current repository", expectScope: "repo:owner/repo"},
+		{name: "organization", opts: []Option{WithOrg("acme")}, expectScope: "org:acme"},
+		{name: "all repositories", opts: []Option{WithAllRepos(true)}, expectScope: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{
+				CurrentUser: "me",
+				AssignedPRs: []models.PullRequestInfo{
+					{Owner: "owner", Repo: "repo", Number: 1},
+					{Owner: "acme", Repo: "api", Number: 7},
+				},
+			}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			prompter := &ui.MockPrompter{SelectedPRNumber: 7}
+			opts := append([]Option{WithReviewers([]string{"alice"}), WithAutoConfirm(true)}, tt.opts...)
+			service := NewReassignService(client, repo, prompter, opts...)
+
+			plan, err := service.ProcessReassignment(context.Background(), []string{"program"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if client.LastScope != tt.expectScope {
+				t.Errorf("Expected scope %q, got %q", tt.expectScope, client.LastScope)
+			}
+			if plan.Owner != "acme" || plan.Repo != "api" || client.LastOwner != "acme" || client.LastRepo != "api" {
+				t.Errorf("Expected the request on acme/api, got plan %s/%s and request %s/%s", plan.Owner, plan.Repo, client.LastOwner, client.LastRepo)
+			}
+		})
+	}
+
+	// A bare PR number cannot be resolved outside a repository
+	service := NewReassignService(&github.MockClient{CurrentUser: "me"}, &github.Repository{}, &ui.MockPrompter{}, WithAllRepos(true))
+	if _, err := service.ProcessReassignment(context.Background(), []string{"program", "7"}); err == nil || !containsString(err.Error(), "needs a repository") {
+		t.Errorf("Expected a missing repository error, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// WithHandoffComment posts a comment on the PR explaining each substitution
+func WithHandoffComment(comment bool) Option {
+	return func(s *ReassignService) {
+		s.handoffComment = comment
+	}
+}
+
+// Substitute hands the pending review requests of primaries over to their first available backup.
+// Without primaries, every requested reviewer who is absent or busy is handed off when a backup is available.
+func (s *ReassignService) Substitute(ctx context.Context, prNumber int, primaries []string, self string) (*models.ReassignPlan, error) {
+	plan, err := s.planSubstitution(ctx, prNumber, primaries, self)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.confirm(ctx, *plan); err != nil {
+		return nil, err
+	}
+	if err := s.handOff(ctx, *plan, self); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// planSubstitution picks a backup for each primary with a pending review request
+func (s *ReassignService) planSubstitution(ctx context.Context, prNumber int, primaries []string, self string) (*models.ReassignPlan, error) {
+	requested, err := s.client.GetRequestedReviewers(ctx, s.repo.GetOwner(), s.repo.GetName(), prNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get requested reviewers: %w", err)
+	}
+	statuses := s.userStatuses(ctx, requested)
+
+	taken := make(map[string]bool, len(requested))
+	for _, reviewer := range requested {
+		taken[strings.ToLower(reviewer)] = true
+	}
+
+	explicit := len(primaries) > 0
+	if explicit {
+		// "@group" hands off the members with a pending request
+		var groupNames []string
+		primaries, groupNames, err = s.expandGroups(primaries, self, func(login string) (bool, error) {
+			return taken[strings.ToLower(login)], nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(primaries) == 0 {
+			return nil, fmt.Errorf("%w: no member of %s has a pending review request on #%d",
+				ErrNothingToRequest, strings.Join(groupNames, ", "), prNumber)
+		}
+		for _, primary := range primaries {
+			if !taken[strings.ToLower(primary)] {
+				return nil, fmt.Errorf("%s has no pending review request on #%d", primary, prNumber)
+			}
+		}
+	} else {
+		for _, reviewer := range requested {
+			if s.unavailable(reviewer, statuses) != "" {
+				primaries = append(primaries, reviewer)
+			}
+		}
+		if len(primaries) == 0 {
+			return nil, fmt.Errorf("%w: every requested reviewer is available", ErrNothingToRequest)
+		}
+	}
+
+	plan := &models.ReassignPlan{
+		Owner:    s.repo.GetOwner(),
+		Repo:     s.repo.GetName(),
+		PRNumber: prNumber,
+	}
+	backupStatuses := s.userStatuses(ctx, s.backupCandidates(primaries))
+	var missing []string
+	for _, primary := range primaries {
+		backup := s.firstAvailableBackup(primary, self, taken, backupStatuses)
+		if backup == "" {
+			missing = append(missing, primary)
+			continue
+		}
+		taken[strings.ToLower(backup)] = true
+		plan.Reviewers = append(plan.Reviewers, backup)
+		plan.Substitutions = append(plan.Substitutions, models.Substitution{
+			Primary: primary,
+			Backup:  backup,
+			Reason:  s.unavailable(primary, statuses),
+		})
+	}
+
+	if len(missing) > 0 && (explicit || len(plan.Substitutions) == 0) {
+		return nil, fmt.Errorf("%w: no available backup for %s", ErrNothingToRequest, strings.Join(missing, ", "))
+	}
+	for _, primary := range missing {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("no available backup for %s; their request is kept", primary))
+	}
+
+	if err := s.ValidateReviewers(plan.Reviewers, self); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// handOff requests the backups before removing the primaries, so a failure never leaves the PR without a reviewer.
+// Both steps are recorded in a single audit entry so that undo can reverse the whole handoff.
+func (s *ReassignService) handOff(ctx context.Context, plan models.ReassignPlan, self string) error {
+	added, err := s.newlyRequested(ctx, plan.PRNumber, plan.Reviewers)
+	if err != nil {
+		return err
+	}
+	entry := audit.Entry{Action: audit.ActionSubstitute, Actor: self, PR: plan.PRNumber, Reviewers: plan.Reviewers, Added: added}
+
+	if err := s.client.ReassignReviewers(ctx, plan.Owner, plan.Repo, plan.PRNumber, plan.Reviewers); err != nil {
+		// Nothing changed, so a failure to record it is not worth reporting over the API error
+		_ = s.record(entry, err)
+		return fmt.Errorf("failed to reassign reviewers: %w", err)
+	}
+
+	primaries := make([]string, len(plan.Substitutions))
+	for i, substitution := range plan.Substitutions {
+		primaries[i] = substitution.Primary
+	}
+	removeErr := s.client.RemoveReviewRequests(ctx, plan.Owner, plan.Repo, plan.PRNumber, primaries)
+	if removeErr == nil {
+		entry.Removed = primaries
+	} else {
+		// The backups were requested, so the entry still lets undo remove them
+		entry.Error = removeErr.Error()
+	}
+	auditErr := s.record(entry, nil)
+	if removeErr != nil {
+		return fmt.Errorf("backups requested but %w", removeErr)
+	}
+	if auditErr != nil {
+		return fmt.Errorf("reviewers handed off but %w", auditErr)
+	}
+
+	if s.handoffComment {
+		if err := s.client.CreateIssueComment(ctx, plan.Owner, plan.Repo, plan.PRNumber, handoffComment(plan.Substitutions)); err != nil {
+			return fmt.Errorf("reviewers handed off but %w", err)
+		}
+	}
+	return nil
+}
+
+// handoffComment explains the substitutions to everyone following the PR
+func handoffComment(substitutions []models.Substitution) string {
+	var b strings.Builder
+	b.WriteString("Handing off pending review requests to backup reviewers:\n\n")
+	for _, substitution := range substitutions {
+		fmt.Fprintf(&b, "- @%s → @%s", substitution.Primary, substitution.Backup)
+		if substitution.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", substitution.Reason)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
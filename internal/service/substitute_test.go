@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// TestSubstitute tests handing pending review requests over to backups
+func TestReassignService_Substitute(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	availability := models.Availability{
+		"alice": {{From: now.Add(-time.Hour), To: now.Add(time.Hour), Reason: "Vacation"}},
+	}
+	backups := map[string][]string{
+		"alice": {"bob", "carol"},
+		"dave":  {"erin"},
+	}
+
+	tests := []struct {
+		name          string
+		primaries     []string
+		requested     []string
+		statuses      map[string]models.UserStatus
+		expectBackups []string
+		expectRemoved []string
+		expectErr     error
+		expectErrText string
+	}{
+		{
+			name:          "absent reviewer is handed off",
+			requested:     []string{"alice", "dave"},
+			expectBackups: []string{"bob"},
+			expectRemoved: []string{"alice"},
+		},
+		{
+			name:          "busy reviewer is handed off",
+			requested:     []string{"dave"},
+			statuses:      map[string]models.UserStatus{"dave": {Busy: true}},
+			expectBackups: []string{"erin"},
+			expectRemoved: []string{"dave"},
+		},
+		{
+			name:          "requested and unavailable backups are passed over",
+			requested:     []string{"alice", "bob"},
+			expectBackups: []string{"carol"},
+			expectRemoved: []string{"alice"},
+		},
+		{
+			name:          "explicit primary",
+			primaries:     []string{"Dave"},
+			requested:     []string{"dave"},
+			expectBackups: []string{"erin"},
+			expectRemoved: []string{"Dave"},
+		},
+		{
+			name:          "group hands off the members with a pending request",
+			primaries:     []string{"@team"},
+			requested:     []string{"dave", "frank"},
+			expectBackups: []string{"erin"},
+			expectRemoved: []string{"dave"},
+		},
+		{
+			name:      "group without a pending request",
+			primaries: []string{"@team"},
+			requested: []string{"frank"},
+			expectErr: ErrNothingToRequest,
+		},
+		{
+			name:          "primary without a pending request",
+			primaries:     []string{"dave"},
+			requested:     []string{"alice"},
+			expectErrText: "dave has no pending review request on #123",
+		},
+		{
+			name:      "everyone available",
+			requested: []string{"dave"},
+			expectErr: ErrNothingToRequest,
+		},
+		{
+			name:      "no available backup",
+			requested: []string{"alice"},
+			statuses:  map[string]models.UserStatus{"bob": {Busy: true}, "carol": {Busy: true}},
+			expectErr: ErrNothingToRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{
+				RequestedReviewers: tt.requested,
+				UserStatuses:       tt.statuses,
+			}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			service := NewReassignService(client, repo, &ui.MockPrompter{}, WithAutoConfirm(true),
+				WithAvailability(availability), WithBackups(backups), WithGroups(map[string][]string{"team": {"dave", "me"}}))
+			service.now = func() time.Time { return now }
+
+			plan, err := service.Substitute(context.Background(), 123, tt.primaries, "me")
+			if tt.expectErr != nil || tt.expectErrText != "" {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				if tt.expectErr != nil && !errors.Is(err, tt.expectErr) {
+					t.Errorf("Expected %v, got %v", tt.expectErr, err)
+				}
+				if tt.expectErrText != "" && err.Error() != tt.expectErrText {
+					t.Errorf("Expected error %q, got %q", tt.expectErrText, err.Error())
+				}
+				if client.ReassignReviewersCalled || client.RemoveReviewRequestsCalled {
+					t.Error("Expected no review requests to change")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if strings.Join(plan.Reviewers, ",") != strings.Join(tt.expectBackups, ",") {
+				t.Errorf("Expected backups %v, got %v", tt.expectBackups, plan.Reviewers)
+			}
+			if strings.Join(client.LastRemoved, ",") != strings.Join(tt.expectRemoved, ",") {
+				t.Errorf("Expected removed %v, got %v", tt.expectRemoved, client.LastRemoved)
+			}
+			if client.CreateIssueCommentCalled {
+				t.Error("Expected no comment without WithHandoffComment")
+			}
+		})
+	}
+}
+
+// TestSubstituteComment tests the handoff comment and the order of the API calls
+func TestReassignService_SubstituteComment(t *testing.T) {
+	now := time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC)
+	client := &github.MockClient{RequestedReviewers: []string{"alice"}}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{}, WithAutoConfirm(true), WithHandoffComment(true),
+		WithAvailability(models.Availability{"alice": {{From: now.Add(-time.Hour), To: now.Add(time.Hour)}}}),
+		WithBackups(map[string][]string{"alice": {"bob"}}))
+	service.now = func() time.Time { return now }
+
+	if _, err := service.Substitute(context.Background(), 123, nil, "me"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !client.ReassignReviewersCalled || !client.RemoveReviewRequestsCalled {
+		t.Error("Expected the backup to be requested and the primary removed")
+	}
+	if !strings.Contains(client.LastComment, "@alice → @bob (alice is away until ") {
+		t.Errorf("Unexpected comment: %q", client.LastComment)
+	}
+
+	// A failed request leaves the primary's request in place
+	client.Reset()
+	client.ReassignError = github.NewNetworkError()
+	if _, err := service.Substitute(context.Background(), 123, nil, "me"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if client.RemoveReviewRequestsCalled || client.CreateIssueCommentCalled {
+		t.Error("Expected the primary to be kept when the backup could not be requested")
+	}
+}
+
+// TestSubstituteAuditLog tests that a handoff is recorded as one entry that undo can reverse
+func TestReassignService_SubstituteAuditLog(t *testing.T) {
+	client := &github.MockClient{RequestedReviewers: []string{"alice"}}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	log := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	service := NewReassignService(client, repo, &ui.MockPrompter{}, WithAutoConfirm(true), WithAuditLog(log),
+		WithBackups(map[string][]string{"alice": {"bob"}}))
+
+	if _, err := service.Substitute(context.Background(), 123, []string{"alice"}, "me"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entries, err := log.Read(audit.Filter{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Action != audit.ActionSubstitute || entry.Outcome != audit.OutcomeSuccess ||
+		!slices.Equal(entry.Added, []string{"bob"}) || !slices.Equal(entry.Removed, []string{"alice"}) {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+}
+
+// TestProcessReassignmentSubstitute tests the substitute strategy
+func TestReassignService_ProcessReassignmentSubstitute(t *testing.T) {
+	client := &github.MockClient{
+		CurrentUser:        "me",
+		RequestedReviewers: []string{"alice"},
+		UserStatuses:       map[string]models.UserStatus{"alice": {Busy: true, Message: "Sick"}},
+	}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	prompter := &ui.MockPrompter{ConfirmedSelection: true}
+	service := NewReassignService(client, repo, prompter, WithStrategy(StrategySubstitute),
+		WithBackups(map[string][]string{"alice": {"bob"}}))
+
+	plan, err := service.ProcessReassignment(context.Background(), []string{"program", "123"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plan.Substitutions) != 1 || plan.Substitutions[0].Backup != "bob" || plan.Substitutions[0].Reason != "alice is busy: Sick" {
+		t.Errorf("Unexpected substitutions: %+v", plan.Substitutions)
+	}
+	if client.GetReviewersAndCommentersCalled {
+		t.Error("Expected the substitute strategy not to look at previous reviewers")
+	}
+}
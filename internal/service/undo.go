@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrCannotUndo is returned when the pull request has moved on since the action being undone
+var ErrCannotUndo = errors.New("cannot undo")
+
+// Undo removes the review requests added by entry, provided every one is still pending
+// and no reviewer has submitted a review since. Nothing is removed otherwise.
+// Reviewers who were already pending before the action stay requested, and the reviewers
+// a substitution handed off are requested again before their backups are removed.
+func (s *ReassignService) Undo(ctx context.Context, entry audit.Entry) (*models.ReassignPlan, error) {
+	owner, name := s.repo.GetOwner(), s.repo.GetName()
+	switch {
+	case entry.IsUndo():
+		return nil, fmt.Errorf("%w: the last action was already undone", ErrCannotUndo)
+	case !strings.EqualFold(entry.Repo, owner+"/"+name):
+		return nil, fmt.Errorf("%w: the action was on %s, not %s/%s", ErrCannotUndo, entry.Repo, owner, name)
+	case entry.Host != "" && !strings.EqualFold(entry.Host, s.client.Host()):
+		return nil, fmt.Errorf("%w: the action was on %s, not %s", ErrCannotUndo, entry.Host, s.client.Host())
+	case entry.Added == nil:
+		return nil, fmt.Errorf("%w: the action was recorded without the reviewers it added", ErrCannotUndo)
+	case len(entry.Added) == 0 && len(entry.Removed) == 0:
+		return nil, fmt.Errorf("%w: every reviewer was already requested before the action", ErrCannotUndo)
+	}
+
+	self, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var requested []string
+	var reviews []models.Review
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		requested, err = s.client.GetRequestedReviewers(gctx, owner, name, entry.PR)
+		return err
+	})
+	g.Go(func() error {
+		var err error
+		reviews, err = s.client.GetReviews(gctx, owner, name, entry.PR)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if problems := undoProblems(entry, requested, reviews); len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrCannotUndo, strings.Join(problems, "; "))
+	}
+
+	// Like a handoff, request before removing so the PR is never left without its reviewers
+	undo := audit.Entry{Action: audit.ActionUndo, Actor: self, PR: entry.PR, Reviewers: entry.Added, Added: entry.Removed}
+	if len(entry.Removed) > 0 {
+		if err := s.client.ReassignReviewers(ctx, owner, name, entry.PR, entry.Removed); err != nil {
+			_ = s.record(undo, err)
+			return nil, fmt.Errorf("failed to request %s again: %w", strings.Join(entry.Removed, ", "), err)
+		}
+	}
+	if len(entry.Added) > 0 {
+		err = s.client.RemoveReviewRequests(ctx, owner, name, entry.PR, entry.Added)
+	}
+	auditErr := s.record(undo, err)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.ReassignPlan{Owner: owner, Repo: name, PRNumber: entry.PR, Reviewers: entry.Added}
+	if auditErr != nil {
+		return plan, fmt.Errorf("review requests removed but %w", auditErr)
+	}
+	return plan, nil
+}
+
+// undoProblems explains why the review requests of entry can no longer be removed safely
+func undoProblems(entry audit.Entry, requested []string, reviews []models.Review) []string {
+	pending := make(map[string]bool, len(requested))
+	for _, login := range requested {
+		pending[strings.ToLower(login)] = true
+	}
+
+	var problems []string
+	for _, reviewer := range entry.Added {
+		if !pending[strings.ToLower(reviewer)] {
+			problems = append(problems, fmt.Sprintf("%s is no longer requested", reviewer))
+			continue
+		}
+		for _, review := range reviews {
+			submitted, err := time.Parse(time.RFC3339, review.SubmittedAt)
+			if err == nil && strings.EqualFold(review.User.Login, reviewer) && submitted.After(entry.Time) {
+				problems = append(problems, fmt.Sprintf("%s has reviewed since (%s)", reviewer, review.SubmittedAt))
+				break
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// TestUndo tests that review requests are only removed while the PR has not moved on
+func TestReassignService_Undo(t *testing.T) {
+	requestedAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	entry := audit.Entry{
+		Time:      requestedAt,
+		Actor:     "currentuser",
+		Host:      "github.com",
+		Repo:      "owner/repo",
+		PR:        123,
+		Reviewers: []string{"user1", "user2"},
+		Added:     []string{"user1", "user2"},
+		Action:    audit.ActionRequest,
+		Outcome:   audit.OutcomeSuccess,
+	}
+
+	tests := []struct {
+		name          string
+		entry         func(audit.Entry) audit.Entry
+		requested     []string
+		reviews       []models.Review
+		expectRemoved []string
+		expectError   string
+	}{
+		{
+			name:          "still pending",
+			requested:     []string{"user1", "user2", "user3"},
+			reviews:       []models.Review{{User: models.User{Login: "user1"}, SubmittedAt: "2024-04-30T09:00:00Z"}},
+			expectRemoved: []string{"user1", "user2"},
+		},
+		{
+			name:          "reviewers pending before the action stay requested",
+			entry:         func(e audit.Entry) audit.Entry { e.Added = []string{"user2"}; return e },
+			requested:     []string{"user1", "user2"},
+			expectRemoved: []string{"user2"},
+		},
+		{
+			name:        "every reviewer was already pending",
+			entry:       func(e audit.Entry) audit.Entry { e.Added = []string{}; return e },
+			requested:   []string{"user1", "user2"},
+			expectError: "already requested",
+		},
+		{
+			name:        "added reviewers not recorded",
+			entry:       func(e audit.Entry) audit.Entry { e.Added = nil; return e },
+			requested:   []string{"user1", "user2"},
+			expectError: "without the reviewers it added",
+		},
+		{
+			name:        "other host",
+			entry:       func(e audit.Entry) audit.Entry { e.Host = "ghe.example.com"; return e },
+			requested:   []string{"user1", "user2"},
+			expectError: "ghe.example.com",
+		},
+		{
+			name:        "no longer requested",
+			requested:   []string{"user1"},
+			expectError: "user2 is no longer requested",
+		},
+		{
+			name:        "reviewed since",
+			requested:   []string{"user1", "user2"},
+			reviews:     []models.Review{{User: models.User{Login: "user2"}, SubmittedAt: "2024-05-01T10:00:00Z"}},
+			expectError: "user2 has reviewed since",
+		},
+		{
+			name:        "already undone",
+			entry:       func(e audit.Entry) audit.Entry { e.Action = audit.ActionUndo; return e },
+			requested:   []string{"user1", "user2"},
+			expectError: "already undone",
+		},
+		{
+			name:        "other repository",
+			entry:       func(e audit.Entry) audit.Entry { e.Repo = "owner/other"; return e },
+			requested:   []string{"user1", "user2"},
+			expectError: "owner/other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &github.MockClient{APIHost: "github.com", CurrentUser: "currentuser", RequestedReviewers: tt.requested, Reviews: tt.reviews}
+			repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+			log := audit.NewLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+			service := NewReassignService(client, repo, &ui.MockPrompter{}, WithAuditLog(log))
+
+			target := entry
+			if tt.entry != nil {
+				target = tt.entry(entry)
+			}
+			plan, err := service.Undo(context.Background(), target)
+
+			if tt.expectError != "" {
+				if !errors.Is(err, ErrCannotUndo) || !containsString(err.Error(), tt.expectError) {
+					t.Fatalf("Expected ErrCannotUndo mentioning %q, got %v", tt.expectError, err)
+				}
+				if client.RemoveReviewRequestsCalled {
+					t.Errorf("Review requests should not be removed")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !client.RemoveReviewRequestsCalled || client.LastPRNumber != 123 || !slices.Equal(client.LastRemoved, tt.expectRemoved) {
+				t.Errorf("Expected %v to be removed from #123, got #%d %v", tt.expectRemoved, client.LastPRNumber, client.LastRemoved)
+			}
+			if plan.PRNumber != 123 {
+				t.Errorf("Expected plan for #123, got #%d", plan.PRNumber)
+			}
+
+			last, err := log.Last()
+			if err != nil || last == nil || !last.IsUndo() {
+				t.Errorf("Expected the undo to be recorded, got %+v, %v", last, err)
+			}
+		})
+	}
+}
+
+// TestUndoSubstitute tests that undoing a handoff requests the primary again before removing the backup
+func TestReassignService_UndoSubstitute(t *testing.T) {
+	entry := audit.Entry{
+		Time:      time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC),
+		Repo:      "owner/repo",
+		PR:        123,
+		Reviewers: []string{"bob"},
+		Added:     []string{"bob"},
+		Removed:   []string{"alice"},
+		Action:    audit.ActionSubstitute,
+		Outcome:   audit.OutcomeSuccess,
+	}
+	client := &github.MockClient{CurrentUser: "currentuser", RequestedReviewers: []string{"bob"}}
+	repo := &github.MockRepository{Owner: "owner", Name: "repo"}
+	service := NewReassignService(client, repo, &ui.MockPrompter{})
+
+	if _, err := service.Undo(context.Background(), entry); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !client.ReassignReviewersCalled {
+		t.Error("Expected alice to be requested again")
+	}
+	if !slices.Equal(client.LastRemoved, []string{"bob"}) {
+		t.Errorf("Expected bob to be removed, got %v", client.LastRemoved)
+	}
+
+	// A failed request keeps the backup
+	client.Reset()
+	client.ReassignError = github.NewNetworkError()
+	if _, err := service.Undo(context.Background(), entry); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if client.RemoveReviewRequestsCalled {
+		t.Error("Expected bob to stay requested when alice could not be requested again")
+	}
+}
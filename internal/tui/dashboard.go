@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// ReRequester re-requests the reviewers blocking a dashboard item and returns who was requested
+type ReRequester func(item models.DashboardItem) ([]string, error)
+
+// reRequestedMsg is sent when a re-request started from the dashboard has finished
+type reRequestedMsg struct {
+	index     int
+	reviewers []string
+	err       error
+}
+
+// DashboardModel is the bubbletea model of the review obligations dashboard
+type DashboardModel struct {
+	items     []models.DashboardItem
+	reRequest ReRequester
+	now       func() time.Time
+
+	cursor  int
+	pending bool
+	done    map[int]bool
+	message string
+	isError bool
+	width   int
+	height  int
+}
+
+// NewDashboardModel creates a dashboard listing items; r re-requests the blockers of the highlighted one
+func NewDashboardModel(items []models.DashboardItem, reRequest ReRequester) DashboardModel {
+	return DashboardModel{
+		items:     items,
+		reRequest: reRequest,
+		now:       time.Now,
+		done:      make(map[int]bool),
+		width:     120,
+		height:    24,
+	}
+}
+
+// RunDashboard starts the full-screen dashboard; cancelling ctx closes it
+func RunDashboard(ctx context.Context, items []models.DashboardItem, reRequest ReRequester) error {
+	if _, err := tea.NewProgram(NewDashboardModel(items, reRequest), tea.WithAltScreen(), tea.WithContext(ctx)).Run(); err != nil {
+		return fmt.Errorf("failed to run TUI: %w", err)
+	}
+	return nil
+}
+
+func (m DashboardModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m DashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case reRequestedMsg:
+		m.pending = false
+		item := m.items[msg.index]
+		ref := fmt.Sprintf("%s/%s#%d", item.PR.Owner, item.PR.Repo, item.PR.Number)
+		if msg.err != nil {
+			m.message, m.isError = fmt.Sprintf("%s: %v", ref, msg.err), true
+		} else {
+			m.done[msg.index] = true
+			m.message, m.isError = fmt.Sprintf("%s: re-requested %s", ref, strings.Join(msg.reviewers, ", ")), false
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+		case "r":
+			if m.pending || len(m.items) == 0 {
+				return m, nil
+			}
+			if item := m.items[m.cursor]; m.done[m.cursor] {
+				m.message, m.isError = fmt.Sprintf("%s/%s#%d was already re-requested", item.PR.Owner, item.PR.Repo, item.PR.Number), false
+				return m, nil
+			}
+			m.pending = true
+			m.message, m.isError = "Re-requesting...", false
+			index, item := m.cursor, m.items[m.cursor]
+			return m, func() tea.Msg {
+				reviewers, err := m.reRequest(item)
+				return reRequestedMsg{index: index, reviewers: reviewers, err: err}
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m DashboardModel) View() string {
+	if len(m.items) == 0 {
+		return "Nothing is waiting on reviews\n"
+	}
+
+	// Lay out the sections first, so that the window can follow the cursor
+	now := m.now()
+	var lines []string
+	cursorLine := 0
+	var role models.DashboardRole
+	for i, item := range m.items {
+		if item.Role != role {
+			role = item.Role
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, cursorStyle.Render(DashboardHeading(role)))
+		}
+
+		line := DashboardLine(item, now, m.width-2)
+		switch {
+		case item.Error != "":
+			line = errorStyle.Render(line)
+		case m.done[i]:
+			line = dimStyle.Render(line)
+		}
+		if i == m.cursor {
+			cursorLine = len(lines)
+			lines = append(lines, cursorStyle.Render("> "+line))
+		} else {
+			lines = append(lines, "  "+line)
+		}
+	}
+
+	// Keep the cursor visible when the list is longer than the screen; the footer takes two lines
+	height := max(m.height-2, 1)
+	start := 0
+	if cursorLine >= height {
+		start = cursorLine - height + 1
+	}
+	var b strings.Builder
+	for _, line := range lines[start:min(start+height, len(lines))] {
+		b.WriteString(line + "\n")
+	}
+
+	footer := dimStyle.Render("↑/↓ move • r re-request blockers • q quit")
+	if m.message != "" {
+		footer = m.message
+		if m.isError {
+			footer = errorStyle.Render(footer)
+		}
+	}
+	return b.String() + "\n" + footer
+}
+
+// DashboardLine renders a dashboard item as one line of at most width cells
+func DashboardLine(item models.DashboardItem, now time.Time, width int) string {
+	ref := fmt.Sprintf("%s/%s#%d", item.PR.Owner, item.PR.Repo, item.PR.Number)
+	line := fmt.Sprintf("%s %s %-4s %s",
+		ui.Fit(ref, 32),
+		ui.Fit(item.PR.Title, 40),
+		ui.FormatAge(item.Waiting(), now),
+		ui.FormatBlockers(item.Blockers, now),
+	)
+	if item.Error != "" {
+		line = fmt.Sprintf("%s %s failed to load: %s", ui.Fit(ref, 32), ui.Fit(item.PR.Title, 40), item.Error)
+	}
+	return ui.Truncate(line, width)
+}
+
+// DashboardHeading titles the section of the dashboard listing items with role
+func DashboardHeading(role models.DashboardRole) string {
+	if role == models.RoleReviewer {
+		return "Waiting on your review"
+	}
+	return "Your PRs waiting on reviewers"
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+func testDashboardItems(now time.Time) []models.DashboardItem {
+	return []models.DashboardItem{
+		{
+			PR:       models.PullRequestInfo{Owner: "acme", Repo: "api", Number: 1, Title: "Add search"},
+			Role:     models.RoleAuthor,
+			Blockers: []models.Blocker{{Login: "alice", Since: now.Add(-3 * 24 * time.Hour)}, {Login: "bob", Stale: true}},
+		},
+		{
+			PR:       models.PullRequestInfo{Owner: "acme", Repo: "web", Number: 2, Title: "Fix layout"},
+			Role:     models.RoleReviewer,
+			Blockers: []models.Blocker{{Login: "me", Since: now.Add(-5 * time.Hour)}},
+		},
+	}
+}
+
+// sendDashboard applies msg and feeds the resulting message back, if any
+func sendDashboard(m DashboardModel, msg tea.Msg) DashboardModel {
+	next, cmd := m.Update(msg)
+	m = next.(DashboardModel)
+	if cmd != nil {
+		if result, ok := cmd().(reRequestedMsg); ok {
+			m = sendDashboard(m, result)
+		}
+	}
+	return m
+}
+
+func TestDashboardModel_View(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	m := NewDashboardModel(testDashboardItems(now), nil)
+	m.now = func() time.Time { return now }
+
+	view := m.View()
+	for _, want := range []string{
+		"Your PRs waiting on reviewers", "acme/api#1", "alice 3d, bob (stale) ?",
+		"Waiting on your review", "acme/web#2", "me 5h",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected view to contain %q:\n%s", want, view)
+		}
+	}
+
+	if empty := NewDashboardModel(nil, nil).View(); !strings.Contains(empty, "Nothing is waiting on reviews") {
+		t.Errorf("Unexpected empty view: %q", empty)
+	}
+}
+
+func TestDashboardModel_ReRequest(t *testing.T) {
+	now := time.Now()
+	var requested []int
+	m := NewDashboardModel(testDashboardItems(now), func(item models.DashboardItem) ([]string, error) {
+		requested = append(requested, item.PR.Number)
+		if item.Role == models.RoleReviewer {
+			return nil, errors.New("waiting on your own review")
+		}
+		return []string{"alice", "bob"}, nil
+	})
+
+	m = sendDashboard(m, key("r"))
+	if len(requested) != 1 || requested[0] != 1 {
+		t.Fatalf("Expected PR #1 to be re-requested, got %v", requested)
+	}
+	if !strings.Contains(m.View(), "acme/api#1: re-requested alice, bob") || !m.done[0] {
+		t.Errorf("Expected a success message, got %q", m.message)
+	}
+
+	// A second r on the same PR does not send another request
+	m = sendDashboard(m, key("r"))
+	if len(requested) != 1 || !strings.Contains(m.message, "acme/api#1 was already re-requested") {
+		t.Errorf("Expected the re-requested PR to be skipped, got %v (%q)", requested, m.message)
+	}
+
+	m = sendDashboard(m, key("down"))
+	m = sendDashboard(m, key("r"))
+	if !m.isError || !strings.Contains(m.message, "waiting on your own review") {
+		t.Errorf("Expected an error message, got %q", m.message)
+	}
+
+	// Keys are ignored while a request is in flight
+	next, _ := m.Update(key("r"))
+	m = next.(DashboardModel)
+	if next, cmd := m.Update(key("r")); cmd != nil || !next.(DashboardModel).pending {
+		t.Error("Expected a second re-request to wait for the first")
+	}
+}
+
+func TestDashboardModel_Scrolls(t *testing.T) {
+	var items []models.DashboardItem
+	for i := 1; i <= 30; i++ {
+		items = append(items, models.DashboardItem{
+			PR:   models.PullRequestInfo{Owner: "acme", Repo: "api", Number: i, Title: "PR"},
+			Role: models.RoleAuthor,
+		})
+	}
+	items[29].Error = "HTTP 404: Not Found"
+	m := sendDashboard(NewDashboardModel(items, nil), tea.WindowSizeMsg{Width: 120, Height: 10})
+
+	for i := 0; i < 29; i++ {
+		m = sendDashboard(m, key("down"))
+	}
+	view := m.View()
+	if lines := strings.Count(view, "\n") + 1; lines > 10 {
+		t.Errorf("Expected the view to fit 10 lines, got %d:\n%s", lines, view)
+	}
+	if !strings.Contains(view, "> acme/api#30") || strings.Contains(view, "acme/api#1 ") {
+		t.Errorf("Expected the window to follow the cursor:\n%s", view)
+	}
+	if !strings.Contains(view, "failed to load: HTTP 404: Not Found") {
+		t.Errorf("Expected the error of the PR that failed to load:\n%s", view)
+	}
+}
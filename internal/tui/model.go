@@ -0,0 +1,662 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// Loader fetches the reviewer statuses of a PR
+type Loader func(pr models.PullRequestInfo) ([]models.ReviewerStatus, error)
+
+// previewLoadedMsg is sent when the preview of a PR has been fetched
+type previewLoadedMsg struct {
+	pr      models.PRRef
+	preview *models.PRPreview
+	err     error
+}
+
+// Result holds the PR and reviewers submitted from the TUI
+type Result struct {
+	Owner     string
+	Repo      string
+	PRNumber  int
+	Reviewers []string
+}
+
+type pane int
+
+const (
+	prPane pane = iota
+	reviewerPane
+)
+
+// actionDoneMsg is sent when a quick action has run
+type actionDoneMsg struct {
+	notice string
+	err    error
+}
+
+// statusesLoadedMsg is sent when the reviewer statuses of a PR have been fetched
+type statusesLoadedMsg struct {
+	pr       models.PRRef
+	statuses []models.ReviewerStatus
+	err      error
+}
+
+var (
+	paneStyle    = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	focusedStyle = paneStyle.BorderForeground(lipgloss.Color("12"))
+	cursorStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	dimStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	errorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	groupStyle   = lipgloss.NewStyle().Bold(true)
+)
+
+// Model is the bubbletea model of the full-screen reviewer picker
+type Model struct {
+	all       []models.PullRequestInfo
+	prs       []models.PullRequestInfo // all, filtered by query and ordered by sortMode
+	load      Loader
+	preview   ui.PreviewLoader
+	groups    map[string][]string
+	actions   *ui.Actions
+	multiRepo bool
+
+	cursor         int
+	reviewerCursor int
+	focus          pane
+	selected       map[string]bool
+
+	sortMode  ui.SortMode
+	query     string
+	filtering bool
+
+	statuses map[models.PRRef][]models.ReviewerStatus
+	errors   map[models.PRRef]error
+	loading  map[models.PRRef]bool
+
+	previews       map[models.PRRef]*models.PRPreview
+	previewErrors  map[models.PRRef]error
+	previewLoading map[models.PRRef]bool
+
+	width   int
+	height  int
+	message string // error shown in the footer
+	notice  string // outcome of a quick action shown in the footer
+	result  *Result
+}
+
+// NewModel creates a model listing prs, loading reviewer details with load
+func NewModel(prs []models.PullRequestInfo, load Loader) Model {
+	return Model{
+		all:       prs,
+		prs:       ui.SortPRs(prs, ui.SortCreated),
+		load:      load,
+		sortMode:  ui.SortCreated,
+		multiRepo: ui.SpansRepositories(prs),
+		selected:  make(map[string]bool),
+		statuses:  make(map[models.PRRef][]models.ReviewerStatus),
+		errors:    make(map[models.PRRef]error),
+		loading:   make(map[models.PRRef]bool),
+
+		previews:       make(map[models.PRRef]*models.PRPreview),
+		previewErrors:  make(map[models.PRRef]error),
+		previewLoading: make(map[models.PRRef]bool),
+
+		width:  120,
+		height: 24,
+	}
+}
+
+// WithGroups lists reviewers under group headers that toggle every member at once
+func (m Model) WithGroups(groups map[string][]string) Model {
+	m.groups = groups
+	return m
+}
+
+// WithPreview describes the highlighted PR above its reviewers, fetching each PR's preview once
+func (m Model) WithPreview(load ui.PreviewLoader) Model {
+	m.preview = load
+	return m
+}
+
+// WithActions enables the quick action keys: open the PR or the reviewer's profile, copy the URL, show diff stats
+func (m Model) WithActions(actions *ui.Actions) Model {
+	m.actions = actions
+	return m
+}
+
+// WithSort lists the PRs in the given order; an empty mode keeps the default
+func (m Model) WithSort(mode ui.SortMode) Model {
+	if mode != "" {
+		m.sortMode = mode
+		m.prs = ui.SortPRs(ui.FilterPRs(m.all, m.query), mode)
+	}
+	return m
+}
+
+// refresh re-applies the query and sort order, highlighting the first PR
+func (m *Model) refresh() {
+	m.prs = ui.SortPRs(ui.FilterPRs(m.all, m.query), m.sortMode)
+	m.focus = prPane
+	m.moveTo(0)
+}
+
+// row is a line of the reviewer pane: a group header, or a reviewer when status is set
+type row struct {
+	group  string
+	status *models.ReviewerStatus
+}
+
+// rows lists the reviewers of the highlighted PR, each under the first group (by name) they belong to.
+// Reviewers outside every group come first, without a header.
+func (m Model) rows() []row {
+	statuses := m.currentStatuses()
+	if len(m.groups) == 0 {
+		rows := make([]row, len(statuses))
+		for i := range statuses {
+			rows[i] = row{status: &statuses[i]}
+		}
+		return rows
+	}
+
+	names := make([]string, 0, len(m.groups))
+	for name := range m.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	groupOf := make(map[string]string)
+	for _, name := range names {
+		for _, member := range m.groups[name] {
+			if _, ok := groupOf[strings.ToLower(member)]; !ok {
+				groupOf[strings.ToLower(member)] = name
+			}
+		}
+	}
+
+	var rows []row
+	members := make(map[string][]row)
+	for i := range statuses {
+		if name, ok := groupOf[strings.ToLower(statuses[i].Login)]; ok {
+			members[name] = append(members[name], row{group: name, status: &statuses[i]})
+		} else {
+			rows = append(rows, row{status: &statuses[i]})
+		}
+	}
+	for _, name := range names {
+		if len(members[name]) > 0 {
+			rows = append(rows, row{group: name})
+			rows = append(rows, members[name]...)
+		}
+	}
+	return rows
+}
+
+// toggle flips the reviewer under the cursor, or every member of the group whose header is under it:
+// members are all selected unless they already are, in which case they are all cleared
+func (m *Model) toggle() {
+	rows := m.rows()
+	if m.reviewerCursor >= len(rows) {
+		return
+	}
+	current := rows[m.reviewerCursor]
+	if current.status != nil {
+		m.selected[current.status.Login] = !m.selected[current.status.Login]
+		return
+	}
+
+	var logins []string
+	allSelected := true
+	for _, r := range rows {
+		if r.status != nil && r.group == current.group {
+			logins = append(logins, r.status.Login)
+			allSelected = allSelected && m.selected[r.status.Login]
+		}
+	}
+	for _, login := range logins {
+		m.selected[login] = !allSelected
+	}
+}
+
+// Result returns the submitted selection, or nil when the TUI was cancelled
+func (m Model) Result() *Result {
+	return m.result
+}
+
+func (m Model) Init() tea.Cmd {
+	return m.loadAround()
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case statusesLoadedMsg:
+		delete(m.loading, msg.pr)
+		if msg.err != nil {
+			m.errors[msg.pr] = msg.err
+		} else {
+			m.statuses[msg.pr] = msg.statuses
+		}
+		return m, nil
+
+	case previewLoadedMsg:
+		delete(m.previewLoading, msg.pr)
+		if msg.err != nil {
+			m.previewErrors[msg.pr] = msg.err
+		} else {
+			delete(m.previewErrors, msg.pr)
+			m.previews[msg.pr] = msg.preview
+		}
+		return m, nil
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.message = msg.err.Error()
+		} else {
+			m.notice = msg.notice
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.message = ""
+	m.notice = ""
+	if m.filtering {
+		return m.handleFilterKey(msg)
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.focus == reviewerPane {
+			if m.reviewerCursor > 0 {
+				m.reviewerCursor--
+			}
+			return m, nil
+		}
+		if m.cursor > 0 {
+			m.moveTo(m.cursor - 1)
+		}
+		return m, m.loadAround()
+
+	case "down", "j":
+		if m.focus == reviewerPane {
+			if m.reviewerCursor < len(m.rows())-1 {
+				m.reviewerCursor++
+			}
+			return m, nil
+		}
+		if m.cursor < len(m.prs)-1 {
+			m.moveTo(m.cursor + 1)
+		}
+		return m, m.loadAround()
+
+	case "tab", "left", "right", "h", "l":
+		if m.focus == reviewerPane {
+			m.focus = prPane
+		} else if len(m.currentStatuses()) > 0 {
+			m.focus = reviewerPane
+		}
+		return m, nil
+
+	case "/":
+		m.filtering = true
+		m.focus = prPane
+		return m, nil
+
+	case "s":
+		m.sortMode = m.sortMode.Next()
+		m.refresh()
+		return m, m.loadAround()
+
+	case "o", "y", "d", "p":
+		return m, m.runAction(msg.String())
+
+	case " ", "x":
+		if m.focus == reviewerPane {
+			m.toggle()
+		}
+		return m, nil
+
+	case "enter":
+		if m.focus == prPane {
+			if len(m.currentStatuses()) > 0 {
+				m.focus = reviewerPane
+			}
+			return m, nil
+		}
+		reviewers := m.selectedReviewers()
+		if len(reviewers) == 0 {
+			m.message = "Select reviewers with space before submitting"
+			return m, nil
+		}
+		pr := m.prs[m.cursor]
+		m.result = &Result{Owner: pr.Owner, Repo: pr.Repo, PRNumber: pr.Number, Reviewers: reviewers}
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// runAction runs the quick action bound to key on the highlighted PR, or reviewer for "p"
+func (m Model) runAction(key string) tea.Cmd {
+	if m.actions == nil || len(m.prs) == 0 {
+		return nil
+	}
+	actions := m.actions
+	pr := m.prs[m.cursor]
+	switch key {
+	case "o":
+		return func() tea.Msg {
+			return actionDoneMsg{notice: "Opened " + pr.URL, err: actions.OpenPR(pr)}
+		}
+	case "y":
+		return func() tea.Msg {
+			return actionDoneMsg{notice: "Copied " + pr.URL, err: actions.CopyURL(pr)}
+		}
+	case "d":
+		return func() tea.Msg {
+			return actionDoneMsg{notice: fmt.Sprintf("%s: %s", m.prLabel(pr), ui.FormatDiffStats(pr))}
+		}
+	case "p":
+		rows := m.rows()
+		if m.focus != reviewerPane || m.reviewerCursor >= len(rows) || rows[m.reviewerCursor].status == nil {
+			return nil
+		}
+		login := rows[m.reviewerCursor].status.Login
+		return func() tea.Msg {
+			return actionDoneMsg{notice: "Opened " + actions.ProfileURL(login), err: actions.OpenProfile(login)}
+		}
+	}
+	return nil
+}
+
+// handleFilterKey edits the PR filter; enter keeps it and esc clears it
+func (m Model) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		m.filtering = false
+		return m, nil
+	case tea.KeyEsc:
+		m.filtering = false
+		m.query = ""
+	case tea.KeyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.refresh()
+	return m, m.loadAround()
+}
+
+// moveTo highlights another PR and clears the reviewer selection
+func (m *Model) moveTo(index int) {
+	m.cursor = index
+	m.reviewerCursor = 0
+	m.selected = make(map[string]bool)
+}
+
+// loadAround loads the highlighted PR and prefetches the next one in the background
+func (m Model) loadAround() tea.Cmd {
+	var cmds []tea.Cmd
+	for i := m.cursor; i <= m.cursor+1 && i < len(m.prs); i++ {
+		pr := m.prs[i]
+		ref := refOf(pr)
+		if _, ok := m.statuses[ref]; !ok && !m.loading[ref] {
+			m.loading[ref] = true
+			cmds = append(cmds, m.loadCmd(pr))
+		}
+		if _, ok := m.previews[ref]; m.preview != nil && !ok && !m.previewLoading[ref] {
+			m.previewLoading[ref] = true
+			cmds = append(cmds, m.previewCmd(pr))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) previewCmd(pr models.PullRequestInfo) tea.Cmd {
+	return func() tea.Msg {
+		preview, err := m.preview(pr)
+		return previewLoadedMsg{pr: refOf(pr), preview: preview, err: err}
+	}
+}
+
+func (m Model) loadCmd(pr models.PullRequestInfo) tea.Cmd {
+	return func() tea.Msg {
+		statuses, err := m.load(pr)
+		return statusesLoadedMsg{pr: refOf(pr), statuses: statuses, err: err}
+	}
+}
+
+// refOf identifies a PR across repositories
+func refOf(pr models.PullRequestInfo) models.PRRef {
+	return models.PRRef{Owner: pr.Owner, Repo: pr.Repo, Number: pr.Number}
+}
+
+// prLabel is "#number", prefixed with the repository when the list spans several
+func (m Model) prLabel(pr models.PullRequestInfo) string {
+	if m.multiRepo {
+		return fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, pr.Number)
+	}
+	return fmt.Sprintf("#%d", pr.Number)
+}
+
+func (m Model) currentStatuses() []models.ReviewerStatus {
+	if len(m.prs) == 0 {
+		return nil
+	}
+	return m.statuses[refOf(m.prs[m.cursor])]
+}
+
+// selectedReviewers returns the selected logins in display order
+func (m Model) selectedReviewers() []string {
+	var reviewers []string
+	for _, status := range m.currentStatuses() {
+		if m.selected[status.Login] {
+			reviewers = append(reviewers, status.Login)
+		}
+	}
+	return reviewers
+}
+
+func (m Model) View() string {
+	if len(m.all) == 0 {
+		return "No assigned pull requests found\n"
+	}
+
+	leftWidth := m.width*2/5 - 4
+	rightWidth := m.width - leftWidth - 8
+	paneHeight := m.height - 4
+	if m.actions != nil {
+		paneHeight-- // the footer lists the quick actions on a second line
+	}
+
+	left, right := paneStyle, paneStyle
+	if m.focus == prPane {
+		left = focusedStyle
+	} else {
+		right = focusedStyle
+	}
+
+	panes := lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		left.Width(leftWidth).Height(paneHeight).Render(m.prListView(leftWidth, paneHeight)),
+		right.Width(rightWidth).Height(paneHeight).Render(m.reviewerView(rightWidth)),
+	)
+
+	help := "↑/↓ move • tab switch pane • space toggle (a whole group on its header) • / filter • s sort • enter submit • q quit"
+	if m.actions != nil {
+		help += "\no open PR • y copy URL • d diff stats • p reviewer profile"
+	}
+	footer := dimStyle.Render(help)
+	if m.filtering {
+		footer = dimStyle.Render("type to filter, e.g. \"author:alice draft:false label:bug fix\" • enter keep • esc clear")
+	}
+	if m.notice != "" {
+		footer = m.notice
+	}
+	if m.message != "" {
+		footer = errorStyle.Render(m.message)
+	}
+	return panes + "\n" + footer
+}
+
+func (m Model) prListView(width, height int) string {
+	var b strings.Builder
+	heading := fmt.Sprintf("sort: %s", m.sortMode)
+	if m.filtering || m.query != "" {
+		heading += " • /" + m.query
+		if m.filtering {
+			heading += "_"
+		}
+	}
+	b.WriteString(dimStyle.Render(ui.Truncate(heading, width)))
+	b.WriteString("\n")
+	height--
+
+	if len(m.prs) == 0 {
+		b.WriteString(dimStyle.Render("No PRs match the filter"))
+		return b.String()
+	}
+
+	// Keep the cursor visible when the list is longer than the pane
+	start := 0
+	if m.cursor >= height {
+		start = m.cursor - height + 1
+	}
+
+	for i := start; i < len(m.prs) && i < start+height; i++ {
+		pr := m.prs[i]
+		line := ui.Truncate(m.prLabel(pr)+" "+pr.Title, width-2)
+		if i == m.cursor {
+			b.WriteString(cursorStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// previewView describes pr once its preview is loaded
+func (m Model) previewView(pr models.PullRequestInfo, width int) string {
+	if err, ok := m.previewErrors[refOf(pr)]; ok {
+		return errorStyle.Render(ui.Truncate(fmt.Sprintf("Failed to load the preview: %v", err), width))
+	}
+	preview, ok := m.previews[refOf(pr)]
+	if !ok {
+		return dimStyle.Render("Loading preview...")
+	}
+	return ui.FormatPRPreview(pr, *preview, width)
+}
+
+func (m Model) reviewerView(width int) string {
+	if len(m.prs) == 0 {
+		return ""
+	}
+	pr := m.prs[m.cursor]
+
+	var b strings.Builder
+	b.WriteString(ui.Truncate(m.prLabel(pr)+" "+pr.Title, width))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render(fmt.Sprintf("by %s • updated %s • %s • %s",
+		pr.User, ui.FormatRelativeTime(pr.UpdatedAt, time.Now()), ui.FormatReviewDecision(pr), ui.FormatChecks(pr.ChecksState))))
+	b.WriteString("\n\n")
+	if m.preview != nil {
+		b.WriteString(m.previewView(pr, width))
+		b.WriteString("\n\n")
+	}
+
+	if err, ok := m.errors[refOf(pr)]; ok {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Failed to load reviewers: %v", err)))
+		return b.String()
+	}
+	statuses, ok := m.statuses[refOf(pr)]
+	if !ok {
+		b.WriteString(dimStyle.Render("Loading reviewers..."))
+		return b.String()
+	}
+	if len(statuses) == 0 {
+		b.WriteString(dimStyle.Render("No available reviewers"))
+		return b.String()
+	}
+
+	for i, r := range m.rows() {
+		focused := m.focus == reviewerPane && i == m.reviewerCursor
+		if r.status == nil {
+			header := groupStyle.Render("@" + r.group)
+			if focused {
+				header = cursorStyle.Render("@" + r.group)
+			}
+			b.WriteString(header + "\n")
+			continue
+		}
+
+		status := *r.status
+		check := "[ ]"
+		if m.selected[status.Login] {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s %s %s %s %s",
+			check,
+			ui.PadRight(status.Login, 20),
+			ui.PadRight(formatState(status.State), 18),
+			ui.PadRight(formatPending(status.Pending), 9),
+			status.LastActivity,
+		)
+		if absence := ui.FormatAbsence(status.Absence); absence != "" {
+			line = ui.Truncate(line+"  "+absence, width)
+		}
+		if userStatus := ui.FormatStatus(status.Status); userStatus != "" {
+			line = ui.Truncate(line+"  "+userStatus, width)
+		}
+		if r.group != "" {
+			line = ui.Truncate("  "+line, width)
+		}
+		if focused {
+			b.WriteString(cursorStyle.Render(line))
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func formatState(state string) string {
+	if state == "" {
+		return "-"
+	}
+	return strings.ToLower(strings.ReplaceAll(state, "_", " "))
+}
+
+func formatPending(pending bool) string {
+	if pending {
+		return "pending"
+	}
+	return ""
+}
@@ -0,0 +1,314 @@
+package tui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+func testStatuses(pr models.PullRequestInfo) ([]models.ReviewerStatus, error) {
+	return []models.ReviewerStatus{
+		{Login: "alice", State: "APPROVED", LastActivity: "2023-01-01T12:00:00Z"},
+		{Login: "bob", Pending: true},
+		{Login: "carol", State: "CHANGES_REQUESTED"},
+	}, nil
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// send applies msg and runs every resulting command synchronously
+func send(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	m = next.(Model)
+	return runCmd(t, m, cmd)
+}
+
+func runCmd(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	if cmd == nil {
+		return m
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, c := range msg {
+			m = runCmd(t, m, c)
+		}
+	case statusesLoadedMsg, previewLoadedMsg:
+		m = send(t, m, msg)
+	}
+	return m
+}
+
+func TestModel_LoadsHighlightedAndNextPR(t *testing.T) {
+	var loaded []int
+	m := NewModel(github.CreateTestPRs(3), func(pr models.PullRequestInfo) ([]models.ReviewerStatus, error) {
+		loaded = append(loaded, pr.Number)
+		return testStatuses(pr)
+	})
+
+	m = runCmd(t, m, m.Init())
+	if len(loaded) != 2 || loaded[0] != 1 || loaded[1] != 2 {
+		t.Fatalf("Expected PR #1 and #2 to be loaded, got %v", loaded)
+	}
+
+	// PR #2 is already prefetched, so only PR #3 is loaded
+	m = send(t, m, key("down"))
+	if len(loaded) != 3 || loaded[2] != 3 {
+		t.Errorf("Expected PR #3 to be prefetched, got %v", loaded)
+	}
+	if m.cursor != 1 {
+		t.Errorf("Expected cursor on second PR, got %d", m.cursor)
+	}
+}
+
+func TestModel_ToggleAndSubmit(t *testing.T) {
+	m := NewModel(github.CreateTestPRs(2), testStatuses)
+	m = runCmd(t, m, m.Init())
+
+	m = send(t, m, key("tab"))
+	if m.focus != reviewerPane {
+		t.Fatalf("Expected focus on reviewer pane")
+	}
+
+	// Submitting without a selection keeps the TUI open
+	next, cmd := m.Update(key("enter"))
+	m = next.(Model)
+	if cmd != nil || m.Result() != nil {
+		t.Fatalf("Expected submit without selection to be rejected")
+	}
+
+	m = send(t, m, key(" "))
+	m = send(t, m, key("down"))
+	m = send(t, m, key("down"))
+	m = send(t, m, key("x"))
+	m = send(t, m, key("down")) // stays on last reviewer
+
+	next, cmd = m.Update(key("enter"))
+	m = next.(Model)
+	if cmd == nil {
+		t.Fatalf("Expected submit to quit the program")
+	}
+
+	result := m.Result()
+	if result == nil {
+		t.Fatalf("Expected a result")
+	}
+	if result.PRNumber != 1 {
+		t.Errorf("Expected PR #1, got #%d", result.PRNumber)
+	}
+	if strings.Join(result.Reviewers, ",") != "alice,carol" {
+		t.Errorf("Expected alice and carol, got %v", result.Reviewers)
+	}
+}
+
+func TestModel_MovingClearsSelection(t *testing.T) {
+	m := NewModel(github.CreateTestPRs(2), testStatuses)
+	m = runCmd(t, m, m.Init())
+
+	m = send(t, m, key("tab"))
+	m = send(t, m, key(" "))
+	m = send(t, m, key("tab"))
+	m = send(t, m, key("down"))
+
+	if len(m.selectedReviewers()) != 0 {
+		t.Errorf("Expected selection to be cleared, got %v", m.selectedReviewers())
+	}
+}
+
+func TestModel_View(t *testing.T) {
+	prs := github.CreateTestPRs(1)
+	prs[0].HeadBranch = "fix/login"
+	m := NewModel(prs, testStatuses).WithPreview(func(pr models.PullRequestInfo) (*models.PRPreview, error) {
+		return &models.PRPreview{BaseBranch: "main", Checks: &models.CheckSummary{Passed: 2}}, nil
+	})
+	if !strings.Contains(m.View(), "Loading reviewers...") {
+		t.Errorf("Expected loading message before statuses arrive")
+	}
+
+	m = runCmd(t, m, m.Init())
+	view := m.View()
+	for _, want := range []string{"#1 Test PR #1", "fix/login → main", "2 passed", "alice", "approved", "pending", "changes requested"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected view to contain %q", want)
+		}
+	}
+}
+
+func TestModel_ToggleGroup(t *testing.T) {
+	groups := map[string][]string{"backend": {"Alice", "carol"}, "frontend": {"carol", "dave"}}
+	m := NewModel(github.CreateTestPRs(1), testStatuses).WithGroups(groups)
+	m = runCmd(t, m, m.Init())
+
+	// bob has no group, then carol is listed under the first group only
+	var lines []string
+	for _, r := range m.rows() {
+		if r.status == nil {
+			lines = append(lines, "@"+r.group)
+		} else {
+			lines = append(lines, r.status.Login)
+		}
+	}
+	if strings.Join(lines, ",") != "bob,@backend,alice,carol" {
+		t.Fatalf("Unexpected rows: %v", lines)
+	}
+	if !strings.Contains(m.View(), "@backend") {
+		t.Errorf("Expected the view to show the group header")
+	}
+
+	m = send(t, m, key("tab"))
+	m = send(t, m, key("down"))
+	m = send(t, m, key(" "))
+	if strings.Join(m.selectedReviewers(), ",") != "alice,carol" {
+		t.Errorf("Expected the group to be selected, got %v", m.selectedReviewers())
+	}
+
+	// With every member selected the header clears the group
+	m = send(t, m, key(" "))
+	if len(m.selectedReviewers()) != 0 {
+		t.Errorf("Expected the group to be cleared, got %v", m.selectedReviewers())
+	}
+
+	// A partially selected group is completed
+	m = send(t, m, key("down"))
+	m = send(t, m, key(" "))
+	m.reviewerCursor = 1
+	m = send(t, m, key(" "))
+	if strings.Join(m.selectedReviewers(), ",") != "alice,carol" {
+		t.Errorf("Expected the group to be completed, got %v", m.selectedReviewers())
+	}
+}
+
+func TestModel_AcrossRepositories(t *testing.T) {
+	prs := []models.PullRequestInfo{
+		{Owner: "acme", Repo: "api", Number: 1, Title: "API change"},
+		{Owner: "acme", Repo: "web", Number: 1, Title: "Web change"},
+	}
+	var loaded []string
+	m := NewModel(prs, func(pr models.PullRequestInfo) ([]models.ReviewerStatus, error) {
+		loaded = append(loaded, pr.Repo)
+		return []models.ReviewerStatus{{Login: "alice-" + pr.Repo}}, nil
+	})
+	m = runCmd(t, m, m.Init())
+
+	// PRs with the same number in different repositories are loaded separately
+	if strings.Join(loaded, ",") != "api,web" {
+		t.Fatalf("Expected both PRs to be loaded, got %v", loaded)
+	}
+	if view := m.View(); !strings.Contains(view, "acme/api#1 API change") || !strings.Contains(view, "acme/web#1 Web change") {
+		t.Errorf("Expected the repository in each row:\n%s", view)
+	}
+
+	m = send(t, m, key("down"))
+	m = send(t, m, key("tab"))
+	m = send(t, m, key(" "))
+	next, _ := m.Update(key("enter"))
+	result := next.(Model).Result()
+	if result == nil || result.Owner != "acme" || result.Repo != "web" || result.PRNumber != 1 {
+		t.Fatalf("Expected acme/web#1, got %+v", result)
+	}
+	if strings.Join(result.Reviewers, ",") != "alice-web" {
+		t.Errorf("Expected alice-web, got %v", result.Reviewers)
+	}
+}
+
+func TestModel_FilterAndSort(t *testing.T) {
+	m := NewModel(github.CreateTestPRs(3), testStatuses)
+	m = runCmd(t, m, m.Init())
+
+	m = send(t, m, key("s"))
+	m = send(t, m, key("s"))
+	if m.prs[0].Number != 3 {
+		t.Fatalf("Expected sorting by number to list #3 first, got #%d", m.prs[0].Number)
+	}
+
+	m = send(t, m, key("/"))
+	for _, r := range "author:user2" {
+		m = send(t, m, key(string(r)))
+	}
+	m = send(t, m, key("enter"))
+	if len(m.prs) != 1 || m.prs[0].Number != 2 {
+		t.Fatalf("Expected the filter to keep only #2, got %v", m.prs)
+	}
+	if _, ok := m.statuses[refOf(m.prs[0])]; !ok {
+		t.Error("Expected the filtered PR to be loaded")
+	}
+	if !strings.Contains(m.View(), "/author:user2") {
+		t.Error("Expected the view to show the filter")
+	}
+
+	m = send(t, m, key("/"))
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.prs) != 3 {
+		t.Errorf("Expected esc to clear the filter, got %d PRs", len(m.prs))
+	}
+}
+
+type stubBrowser struct {
+	urls []string
+}
+
+func (b *stubBrowser) Browse(url string) error {
+	b.urls = append(b.urls, url)
+	return nil
+}
+
+func TestModel_QuickActions(t *testing.T) {
+	browser := &stubBrowser{}
+	var out bytes.Buffer
+	prs := github.CreateTestPRs(1)
+	prs[0].URL = "https://github.com/acme/api/pull/1"
+	prs[0].Additions, prs[0].Deletions, prs[0].ChangedFiles = 10, 2, 3
+	m := NewModel(prs, testStatuses).WithActions(&ui.Actions{Browser: browser, Out: &out})
+	m = runActionKeys(t, runCmd(t, m, m.Init()), "o")
+
+	if len(browser.urls) != 1 || browser.urls[0] != prs[0].URL {
+		t.Errorf("Expected the PR to be opened, got %v", browser.urls)
+	}
+
+	m = runActionKeys(t, m, "y")
+	if !strings.Contains(out.String(), "\x1b]52;c;") || m.notice != "Copied "+prs[0].URL {
+		t.Errorf("Expected the URL to be copied, got %q (%q)", out.String(), m.notice)
+	}
+
+	m = runActionKeys(t, m, "d")
+	if !strings.Contains(m.View(), "#1: +10 -2 in 3 files") {
+		t.Errorf("Expected the diff stats in the footer, got %q", m.notice)
+	}
+
+	// The profile opens for the reviewer under the cursor only
+	m = runActionKeys(t, m, "p")
+	m = runActionKeys(t, send(t, m, key("tab")), "p")
+	if len(browser.urls) != 2 || browser.urls[1] != "https://github.com/alice" {
+		t.Errorf("Expected alice's profile to be opened, got %v", browser.urls)
+	}
+}
+
+// runActionKeys presses key and delivers the outcome of the quick action it starts
+func runActionKeys(t *testing.T, m Model, key string) Model {
+	t.Helper()
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	m = next.(Model)
+	if cmd != nil {
+		next, _ = m.Update(cmd())
+		m = next.(Model)
+	}
+	return m
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// Run starts the full-screen picker and returns the submitted selection; cancelling ctx closes it.
+// Reviewers are listed under the header of the first group they belong to; PRs are first listed in sortMode order.
+// With preview, the highlighted PR is described above its reviewers.
+func Run(ctx context.Context, prs []models.PullRequestInfo, groups map[string][]string, sortMode ui.SortMode, actions *ui.Actions, preview ui.PreviewLoader, load Loader) (*Result, error) {
+	if len(prs) == 0 {
+		return nil, fmt.Errorf("no assigned pull requests found")
+	}
+
+	// tea.Printf prints nothing on the alternate screen, so the clipboard escape sequence of the copy action goes
+	// through the program's own output, which keeps it from being written in the middle of a frame
+	out := &terminal{File: os.Stdout}
+	if actions != nil {
+		copied := *actions
+		copied.Out = out
+		actions = &copied
+	}
+
+	model := NewModel(prs, load).WithGroups(groups).WithSort(sortMode).WithActions(actions).WithPreview(preview)
+	final, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx), tea.WithOutput(out)).Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run TUI: %w", err)
+	}
+
+	result := final.(Model).Result()
+	if result == nil {
+		return nil, fmt.Errorf("reviewer selection cancelled")
+	}
+	return result, nil
+}
+
+// terminal serializes the writes to the terminal; the renderer writes each frame at once.
+// It embeds the *os.File so that the program still detects the terminal and its size.
+type terminal struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (t *terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.File.Write(p)
+}
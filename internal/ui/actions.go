@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cli/go-gh/v2/pkg/browser"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// Browser opens URLs; tests replace it so that no browser is launched
+type Browser interface {
+	Browse(url string) error
+}
+
+// Actions are the quick actions offered while picking a PR or a reviewer
+type Actions struct {
+	Browser Browser
+	Out     io.Writer // receives the clipboard escape sequence
+	Host    string    // host of reviewer profiles; empty means github.com
+}
+
+// NewActions opens URLs with the browser configured for gh: $GH_BROWSER, the gh config, then $BROWSER
+func NewActions(host string) *Actions {
+	return &Actions{
+		Browser: browser.New("", os.Stderr, os.Stderr),
+		Out:     os.Stdout,
+		Host:    host,
+	}
+}
+
+// OpenPR opens the PR in the browser
+func (a *Actions) OpenPR(pr models.PullRequestInfo) error {
+	if pr.URL == "" {
+		return fmt.Errorf("no URL known for PR #%d", pr.Number)
+	}
+	return a.Browser.Browse(pr.URL)
+}
+
+// CopyURL puts the PR URL on the clipboard with an OSC 52 escape sequence, which also works over SSH
+func (a *Actions) CopyURL(pr models.PullRequestInfo) error {
+	if pr.URL == "" {
+		return fmt.Errorf("no URL known for PR #%d", pr.Number)
+	}
+	_, err := fmt.Fprintf(a.Out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(pr.URL)))
+	return err
+}
+
+// OpenProfile opens the GitHub profile of login in the browser
+func (a *Actions) OpenProfile(login string) error {
+	return a.Browser.Browse(a.ProfileURL(login))
+}
+
+// ProfileURL is the address of a user's profile on the actions' host
+func (a *Actions) ProfileURL(login string) string {
+	host := a.Host
+	if host == "" {
+		host = "github.com"
+	}
+	return fmt.Sprintf("https://%s/%s", host, login)
+}
+
+// FormatDiffStats summarizes the size of a PR, e.g. "+120 -30 in 5 files"
+func FormatDiffStats(pr models.PullRequestInfo) string {
+	return fmt.Sprintf("+%d -%d in %d %s", pr.Additions, pr.Deletions, pr.ChangedFiles, plural(pr.ChangedFiles, "file", "files"))
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+type stubBrowser struct {
+	urls []string
+}
+
+func (b *stubBrowser) Browse(url string) error {
+	b.urls = append(b.urls, url)
+	return nil
+}
+
+func TestActions(t *testing.T) {
+	browser := &stubBrowser{}
+	var out bytes.Buffer
+	actions := &Actions{Browser: browser, Out: &out, Host: "ghe.example.com"}
+	pr := models.PullRequestInfo{Number: 42, URL: "https://ghe.example.com/acme/api/pull/42"}
+
+	if err := actions.OpenPR(pr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := actions.OpenProfile("alice"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(browser.urls) != 2 || browser.urls[0] != pr.URL || browser.urls[1] != "https://ghe.example.com/alice" {
+		t.Errorf("Expected the PR and the profile to be opened, got %v", browser.urls)
+	}
+
+	if err := actions.CopyURL(pr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(pr.URL)) + "\a"
+	if out.String() != expected {
+		t.Errorf("Expected an OSC 52 sequence %q, got %q", expected, out.String())
+	}
+
+	if err := actions.OpenPR(models.PullRequestInfo{Number: 7}); err == nil {
+		t.Error("Expected an error for a PR without a URL")
+	}
+	if len(browser.urls) != 2 {
+		t.Errorf("Expected no browser for a PR without a URL, got %v", browser.urls)
+	}
+}
+
+func TestActions_ProfileURLDefaultsToGitHub(t *testing.T) {
+	if got := (&Actions{}).ProfileURL("alice"); got != "https://github.com/alice" {
+		t.Errorf("Expected a github.com profile, got %q", got)
+	}
+}
+
+func TestFormatDiffStats(t *testing.T) {
+	tests := []struct {
+		pr       models.PullRequestInfo
+		expected string
+	}{
+		{models.PullRequestInfo{Additions: 120, Deletions: 30, ChangedFiles: 5}, "+120 -30 in 5 files"},
+		{models.PullRequestInfo{Additions: 1, ChangedFiles: 1}, "+1 -0 in 1 file"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDiffStats(tt.pr); got != tt.expected {
+			t.Errorf("FormatDiffStats() = %q, want %q", got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+var (
+	// ErrNotInteractive is returned when confirmation is required but stdin is not a terminal
+	ErrNotInteractive = errors.New("stdin is not a terminal; re-run with --yes to confirm without prompting")
+	// ErrConfirmationCancelled is returned when the prompt is interrupted or stdin is closed
+	ErrConfirmationCancelled = errors.New("confirmation cancelled")
+)
+
+// Confirmer asks the user to confirm a reassignment
+type Confirmer struct {
+	In      io.Reader
+	Out     io.Writer
+	IsTTY   bool
+	Default bool          // answer used for an empty line
+	Timeout time.Duration // zero waits forever
+}
+
+type readResult struct {
+	line string
+	err  error
+}
+
+// Confirm prints the plan and waits for a yes/no answer until ctx is cancelled
+func (c *Confirmer) Confirm(ctx context.Context, plan models.ReassignPlan) (bool, error) {
+	if !c.IsTTY {
+		return false, ErrNotInteractive
+	}
+
+	fmt.Fprint(c.Out, FormatPlan(plan))
+
+	var timeout <-chan time.Time
+	if c.Timeout > 0 {
+		timer := time.NewTimer(c.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	// Reading happens in the background so the prompt can be interrupted or time out
+	lines := make(chan readResult, 1)
+	reader := bufio.NewReader(c.In)
+	readLine := func() {
+		line, err := reader.ReadString('\n')
+		lines <- readResult{line: line, err: err}
+	}
+
+	for {
+		fmt.Fprintf(c.Out, "Re-request review? %s: ", c.choices())
+		go readLine()
+
+		select {
+		case res := <-lines:
+			answer := strings.ToLower(strings.TrimSpace(res.line))
+			if res.err != nil && (res.err != io.EOF || answer == "") {
+				fmt.Fprintln(c.Out)
+				return false, ErrConfirmationCancelled
+			}
+			switch answer {
+			case "":
+				return c.Default, nil
+			case "yes", "y":
+				return true, nil
+			case "no", "n":
+				return false, nil
+			default:
+				fmt.Fprintln(c.Out, "Please enter 'y' or 'n'.")
+			}
+		case <-ctx.Done():
+			fmt.Fprintln(c.Out)
+			return false, ErrConfirmationCancelled
+		case <-timeout:
+			fmt.Fprintln(c.Out)
+			return false, fmt.Errorf("no answer within %s", c.Timeout)
+		}
+	}
+}
+
+func (c *Confirmer) choices() string {
+	if c.Default {
+		return "[Y/n]"
+	}
+	return "[y/N]"
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+func testPlan() models.ReassignPlan {
+	return models.ReassignPlan{Owner: "owner", Repo: "repo", PRNumber: 123, Reviewers: []string{"alice", "bob"}}
+}
+
+func TestConfirmer_Confirm(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		defaultAnswer bool
+		expected      bool
+		expectedErr   error
+	}{
+		{name: "yes", input: "y\n", expected: true},
+		{name: "full word no", input: "NO\n", expected: false},
+		{name: "empty line uses default yes", input: "\n", defaultAnswer: true, expected: true},
+		{name: "empty line uses default no", input: "\n", expected: false},
+		{name: "invalid answer then yes", input: "maybe\nyes\n", expected: true},
+		{name: "answer without trailing newline", input: "y", expected: true},
+		{name: "closed stdin", input: "", expectedErr: ErrConfirmationCancelled},
+		{name: "closed stdin after invalid answer", input: "maybe\n", expectedErr: ErrConfirmationCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			c := &Confirmer{In: strings.NewReader(tt.input), Out: &out, IsTTY: true, Default: tt.defaultAnswer}
+
+			got, err := c.Confirm(context.Background(), testPlan())
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Confirm() error = %v, want %v", err, tt.expectedErr)
+			}
+			if got != tt.expected {
+				t.Errorf("Confirm() = %v, want %v", got, tt.expected)
+			}
+			if !strings.Contains(out.String(), "Reviewers:    alice, bob") {
+				t.Errorf("Expected summary of affected reviewers, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestConfirmer_NotInteractive(t *testing.T) {
+	var out bytes.Buffer
+	c := &Confirmer{In: strings.NewReader("y\n"), Out: &out}
+
+	if _, err := c.Confirm(context.Background(), testPlan()); !errors.Is(err, ErrNotInteractive) {
+		t.Fatalf("Expected ErrNotInteractive, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no prompt to be printed, got %q", out.String())
+	}
+}
+
+func TestConfirmer_Timeout(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	c := &Confirmer{In: r, Out: io.Discard, IsTTY: true, Timeout: 10 * time.Millisecond}
+	if _, err := c.Confirm(context.Background(), testPlan()); err == nil || !strings.Contains(err.Error(), "no answer within") {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestFormatPlan(t *testing.T) {
+	expected := "Repository:   owner/repo\nPull request: #123\nReviewers:    alice, bob\n"
+	if got := FormatPlan(testPlan()); got != expected {
+		t.Errorf("FormatPlan() = %q, want %q", got, expected)
+	}
+
+	plan := testPlan()
+	plan.Warnings = []string{"bob is busy: On vacation"}
+	expected += "Warning:      bob is busy: On vacation\n"
+	if got := FormatPlan(plan); got != expected {
+		t.Errorf("FormatPlan() = %q, want %q", got, expected)
+	}
+}
+
+func TestConfirmer_Cancelled(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := &Confirmer{In: r, Out: io.Discard, IsTTY: true}
+	if _, err := c.Confirm(ctx, testPlan()); !errors.Is(err, ErrConfirmationCancelled) {
+		t.Fatalf("Expected ErrConfirmationCancelled, got %v", err)
+	}
+}
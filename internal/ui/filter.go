@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// PRFilter is a parsed picker query: qualifiers such as "author:alice draft:false label:bug repo:api"
+// plus free-text terms matched fuzzily against the row
+type PRFilter struct {
+	Terms  []string
+	Author string
+	Draft  *bool
+	Labels []string
+	Repo   string
+}
+
+// ParsePRFilter splits a picker query into qualifiers and free-text terms; unknown qualifiers are free text
+func ParsePRFilter(query string) PRFilter {
+	var f PRFilter
+	for _, field := range strings.Fields(query) {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			f.Terms = append(f.Terms, field)
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "author":
+			f.Author = strings.TrimPrefix(value, "@")
+		case "draft":
+			draft := value == "true" || value == "yes"
+			if draft || value == "false" || value == "no" {
+				f.Draft = &draft
+				continue
+			}
+			f.Terms = append(f.Terms, field)
+		case "label":
+			f.Labels = append(f.Labels, value)
+		case "repo":
+			f.Repo = value
+		default:
+			f.Terms = append(f.Terms, field)
+		}
+	}
+	return f
+}
+
+// Match reports whether pr satisfies every qualifier and fuzzily contains every term in its
+// number, title, author, repository, head branch or labels
+func (f PRFilter) Match(pr models.PullRequestInfo) bool {
+	if f.Author != "" && !strings.EqualFold(pr.User, f.Author) {
+		return false
+	}
+	if f.Draft != nil && pr.Draft != *f.Draft {
+		return false
+	}
+	for _, label := range f.Labels {
+		if !containsFold(pr.Labels, label) {
+			return false
+		}
+	}
+	if f.Repo != "" && !strings.Contains(strings.ToLower(pr.Owner+"/"+pr.Repo), strings.ToLower(f.Repo)) {
+		return false
+	}
+	text := searchText(pr)
+	for _, term := range f.Terms {
+		if !FuzzyMatch(term, text) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterPRs returns the PRs matching query, keeping their order
+func FilterPRs(prs []models.PullRequestInfo, query string) []models.PullRequestInfo {
+	filter := ParsePRFilter(query)
+	var matched []models.PullRequestInfo
+	for _, pr := range prs {
+		if filter.Match(pr) {
+			matched = append(matched, pr)
+		}
+	}
+	return matched
+}
+
+// FuzzyMatch reports whether the runes of pattern appear in text in order, ignoring case
+func FuzzyMatch(pattern, text string) bool {
+	remaining := []rune(strings.ToLower(pattern))
+	if len(remaining) == 0 {
+		return true
+	}
+	for _, r := range strings.ToLower(text) {
+		if r == remaining[0] {
+			remaining = remaining[1:]
+			if len(remaining) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func searchText(pr models.PullRequestInfo) string {
+	return fmt.Sprintf("#%d %s %s %s/%s %s %s", pr.Number, pr.Title, pr.User, pr.Owner, pr.Repo, pr.HeadBranch, strings.Join(pr.Labels, " "))
+}
+
+func containsFold(values []string, want string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// SortMode orders the PRs of the picker
+type SortMode string
+
+const (
+	// SortCreated lists the newest PRs first, as returned by the search
+	SortCreated SortMode = "created"
+	// SortUpdated lists the most recently updated PRs first
+	SortUpdated SortMode = "updated"
+	// SortNumber lists the highest PR numbers first
+	SortNumber SortMode = "number"
+	// SortReview lists PRs waiting on review first, then those with changes requested, then approved ones
+	SortReview SortMode = "review"
+)
+
+// SortModes lists the sort modes in the order the picker cycles through them
+var SortModes = []SortMode{SortCreated, SortUpdated, SortNumber, SortReview}
+
+// ParseSortMode validates a sort mode given on the command line; empty means SortCreated
+func ParseSortMode(name string) (SortMode, error) {
+	if name == "" {
+		return SortCreated, nil
+	}
+	for _, mode := range SortModes {
+		if string(mode) == strings.ToLower(name) {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort %q: expected created, updated, number or review", name)
+}
+
+// Next returns the sort mode after m, wrapping around
+func (m SortMode) Next() SortMode {
+	for i, mode := range SortModes {
+		if mode == m {
+			return SortModes[(i+1)%len(SortModes)]
+		}
+	}
+	return SortCreated
+}
+
+// SortPRs returns a copy of prs ordered by mode; ties keep their original order
+func SortPRs(prs []models.PullRequestInfo, mode SortMode) []models.PullRequestInfo {
+	sorted := append([]models.PullRequestInfo(nil), prs...)
+	var less func(a, b models.PullRequestInfo) bool
+	switch mode {
+	case SortUpdated:
+		// RFC 3339 timestamps in UTC sort lexically
+		less = func(a, b models.PullRequestInfo) bool { return a.UpdatedAt > b.UpdatedAt }
+	case SortNumber:
+		less = func(a, b models.PullRequestInfo) bool { return a.Number > b.Number }
+	case SortReview:
+		less = func(a, b models.PullRequestInfo) bool { return reviewRank(a) < reviewRank(b) }
+	default:
+		less = func(a, b models.PullRequestInfo) bool { return a.CreatedAt > b.CreatedAt }
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	return sorted
+}
+
+// reviewRank puts the PRs blocked on review first and drafts last
+func reviewRank(pr models.PullRequestInfo) int {
+	switch {
+	case pr.Draft:
+		return 5
+	case pr.ReviewDecision == "REVIEW_REQUIRED":
+		return 0
+	case pr.ReviewDecision == "CHANGES_REQUESTED":
+		return 1
+	case pr.ReviewDecision == "":
+		return 2
+	default:
+		return 3
+	}
+}
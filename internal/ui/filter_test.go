@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+func TestPRFilter_Match(t *testing.T) {
+	pr := models.PullRequestInfo{
+		Number:     42,
+		Title:      "Fix login redirect",
+		User:       "Alice",
+		Owner:      "acme",
+		Repo:       "api",
+		Labels:     []string{"Bug", "backend"},
+		HeadBranch: "fix/login",
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"empty", "", true},
+		{"fuzzy title ignoring case", "FLR", true},
+		{"fuzzy out of order", "tcerider", false},
+		{"number", "#42", true},
+		{"author", "author:alice", true},
+		{"author with at", "author:@ALICE", true},
+		{"other author", "author:bob", false},
+		{"draft false", "draft:false", true},
+		{"draft true", "draft:true", false},
+		{"label ignoring case", "label:bug", true},
+		{"every label", "label:bug label:frontend", false},
+		{"repo", "repo:acme/api", true},
+		{"qualifiers and terms", "author:alice label:backend login", true},
+		{"unknown qualifier is a term", "is:open", false},
+		{"invalid draft is a term", "draft:maybe", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParsePRFilter(tt.query).Match(pr); got != tt.want {
+				t.Errorf("Expected Match(%q) = %v, got %v", tt.query, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSortPRs(t *testing.T) {
+	prs := []models.PullRequestInfo{
+		{Number: 1, CreatedAt: "2024-01-01T00:00:00Z", UpdatedAt: "2024-03-01T00:00:00Z", ReviewDecision: "APPROVED"},
+		{Number: 3, CreatedAt: "2024-01-03T00:00:00Z", UpdatedAt: "2024-01-03T00:00:00Z", Draft: true},
+		{Number: 2, CreatedAt: "2024-01-02T00:00:00Z", UpdatedAt: "2024-02-01T00:00:00Z", ReviewDecision: "REVIEW_REQUIRED"},
+		{Number: 4, CreatedAt: "2023-12-01T00:00:00Z", UpdatedAt: "2023-12-01T00:00:00Z", ReviewDecision: "CHANGES_REQUESTED"},
+	}
+
+	tests := []struct {
+		mode SortMode
+		want []int
+	}{
+		{SortCreated, []int{3, 2, 1, 4}},
+		{SortUpdated, []int{1, 2, 3, 4}},
+		{SortNumber, []int{4, 3, 2, 1}},
+		{SortReview, []int{2, 4, 1, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			sorted := SortPRs(prs, tt.mode)
+			for i, number := range tt.want {
+				if sorted[i].Number != number {
+					t.Fatalf("Expected order %v, got #%d at %d", tt.want, sorted[i].Number, i)
+				}
+			}
+		})
+	}
+
+	if prs[0].Number != 1 {
+		t.Error("Expected SortPRs to leave its input untouched")
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	if mode, err := ParseSortMode(""); err != nil || mode != SortCreated {
+		t.Errorf("Expected the default sort to be created, got %q (%v)", mode, err)
+	}
+	if mode, err := ParseSortMode("Review"); err != nil || mode != SortReview {
+		t.Errorf("Expected review, got %q (%v)", mode, err)
+	}
+	if _, err := ParseSortMode("stars"); err == nil {
+		t.Error("Expected an error for an unknown sort")
+	}
+	if SortReview.Next() != SortCreated {
+		t.Errorf("Expected the sort to wrap around, got %q", SortReview.Next())
+	}
+}
@@ -1,11 +1,32 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mattn/go-runewidth"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 )
 
+// Ellipsis ends text cut by Truncate
+const Ellipsis = "…"
+
+// Truncate cuts str to at most width display columns, ending it with an ellipsis when it is cut.
+// Wide characters such as CJK and emoji take two columns and are never split.
+func Truncate(str string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(str, width, Ellipsis)
+}
+
+// Fit truncates or pads str to exactly width display columns
+func Fit(str string, width int) string {
+	return PadRight(Truncate(str, width), width)
+}
+
+// PadRight pads str with spaces to width display columns; longer strings are left as they are
 func PadRight(str string, width int) string {
 	w := runewidth.StringWidth(str)
 	if w < width {
@@ -13,3 +34,209 @@ func PadRight(str string, width int) string {
 	}
 	return str
 }
+
+// FormatChanges describes what changed since the reviewer's last review
+func FormatChanges(reviewer models.ReviewerCandidate) string {
+	switch {
+	case reviewer.LastReviewedCommit == "":
+		return "not reviewed yet"
+	case reviewer.Changes == nil:
+		return "reviewed commit no longer available"
+	case reviewer.Changes.Commits == 0:
+		return "up to date"
+	}
+
+	c := reviewer.Changes
+	return fmt.Sprintf(
+		"%d %s, %d %s changed (+%d/-%d) since last review",
+		c.Commits, plural(c.Commits, "commit", "commits"),
+		c.FilesChanged, plural(c.FilesChanged, "file", "files"),
+		c.Additions, c.Deletions,
+	)
+}
+
+// FormatStatus describes the user's GitHub status, or "" when none is set
+func FormatStatus(status *models.UserStatus) string {
+	switch {
+	case status == nil:
+		return ""
+	case status.Busy && status.Message != "":
+		return "busy: " + status.Message
+	case status.Busy:
+		return "busy"
+	default:
+		return status.Message
+	}
+}
+
+// FormatAbsence describes an absence as "away until <return date> (<reason>)", or "" when there is none
+func FormatAbsence(absence *models.Absence) string {
+	if absence == nil {
+		return ""
+	}
+	to := absence.To.Local()
+	layout := "Mon Jan 2 15:04"
+	if to.Hour() == 0 && to.Minute() == 0 {
+		layout = "Mon Jan 2"
+	}
+	description := "away until " + to.Format(layout)
+	if absence.Reason != "" {
+		description += " (" + absence.Reason + ")"
+	}
+	return description
+}
+
+// FormatAge renders the time elapsed since t compactly, e.g. "45m", "5h" or "3d"; "?" when t is unknown
+func FormatAge(t, now time.Time) string {
+	if t.IsZero() {
+		return "?"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+}
+
+// FormatRelativeTime renders an RFC 3339 timestamp relative to now, e.g. "3d ago"; invalid timestamps are returned as is
+func FormatRelativeTime(timestamp string, now time.Time) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	if age := FormatAge(t, now); age != "now" {
+		return age + " ago"
+	}
+	return "just now"
+}
+
+// FormatReviewDecision describes where a PR stands on review
+func FormatReviewDecision(pr models.PullRequestInfo) string {
+	switch {
+	case pr.Draft:
+		return "draft"
+	case pr.ReviewDecision == "":
+		return "-"
+	default:
+		return strings.ToLower(strings.ReplaceAll(pr.ReviewDecision, "_", " "))
+	}
+}
+
+// FormatChecks describes the combined status of a PR's checks
+func FormatChecks(state string) string {
+	switch state {
+	case "SUCCESS":
+		return "✓ passing"
+	case "FAILURE", "ERROR":
+		return "✗ failing"
+	case "PENDING", "EXPECTED":
+		return "● pending"
+	default:
+		return "- no checks"
+	}
+}
+
+// FormatBlockers lists who a PR waits on and for how long, e.g. "alice 3d, bob (stale) 5h"
+func FormatBlockers(blockers []models.Blocker, now time.Time) string {
+	parts := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		parts[i] = blocker.Login
+		if blocker.Stale {
+			parts[i] += " (stale)"
+		}
+		parts[i] += " " + FormatAge(blocker.Since, now)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatCheckSummary counts a PR's checks by outcome, e.g. "3 passed, 1 failed"
+func FormatCheckSummary(checks *models.CheckSummary) string {
+	if checks == nil {
+		return "no checks"
+	}
+	var parts []string
+	for _, count := range []struct {
+		n     int
+		label string
+	}{{checks.Passed, "passed"}, {checks.Failed, "failed"}, {checks.Pending, "pending"}, {checks.Skipped, "skipped"}} {
+		if count.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count.n, count.label))
+		}
+	}
+	if len(parts) == 0 {
+		return "no checks"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// previewBodyLines is how much of the PR description the preview shows
+const previewBodyLines = 3
+
+// FormatPRPreview describes the PR highlighted in a picker: branches, requested reviewers, the latest
+// review of each reviewer, checks and the start of the description, each line at most width columns
+func FormatPRPreview(pr models.PullRequestInfo, preview models.PRPreview, width int) string {
+	reviews := make([]string, len(preview.LatestReviews))
+	for i, review := range preview.LatestReviews {
+		reviews[i] = review.User.Login + " " + strings.ToLower(strings.ReplaceAll(review.State, "_", " "))
+	}
+
+	lines := []string{
+		fmt.Sprintf("Branches:  %s → %s", orDash(pr.HeadBranch), orDash(preview.BaseBranch)),
+		"Requested: " + orDash(strings.Join(preview.ReviewRequests, ", ")),
+		"Reviews:   " + orDash(strings.Join(reviews, ", ")),
+		"Checks:    " + FormatCheckSummary(preview.Checks),
+	}
+
+	var body []string
+	for _, line := range strings.Split(preview.Body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			body = append(body, line)
+		}
+	}
+	if len(body) > previewBodyLines {
+		body = append(body[:previewBodyLines-1], "…")
+	}
+	if len(body) > 0 {
+		lines = append(append(lines, ""), body...)
+	}
+
+	for i, line := range lines {
+		lines[i] = Truncate(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+// FormatPlan summarizes exactly which PR, repository and reviewers will be affected
+func FormatPlan(plan models.ReassignPlan) string {
+	summary := fmt.Sprintf(
+		"Repository:   %s/%s\nPull request: #%d\nReviewers:    %s\n",
+		plan.Owner, plan.Repo, plan.PRNumber, strings.Join(plan.Reviewers, ", "),
+	)
+	for _, substitution := range plan.Substitutions {
+		summary += fmt.Sprintf("Handoff:      %s -> %s\n", substitution.Primary, substitution.Backup)
+	}
+	for _, warning := range plan.Warnings {
+		summary += "Warning:      " + warning + "\n"
+	}
+	return summary
+}
@@ -1,7 +1,11 @@
 package ui
 
 import (
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 )
 
 func TestPadRight(t *testing.T) {
@@ -58,3 +62,223 @@ func TestPadRight(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		reviewer models.ReviewerCandidate
+		expected string
+	}{
+		{
+			name:     "not reviewed",
+			reviewer: models.ReviewerCandidate{Login: "alice"},
+			expected: "not reviewed yet",
+		},
+		{
+			name:     "reviewed commit unavailable",
+			reviewer: models.ReviewerCandidate{Login: "alice", LastReviewedCommit: "abc"},
+			expected: "reviewed commit no longer available",
+		},
+		{
+			name: "up to date",
+			reviewer: models.ReviewerCandidate{
+				Login:              "alice",
+				LastReviewedCommit: "abc",
+				Changes:            &models.CompareSummary{BaseCommit: "abc", HeadCommit: "abc"},
+			},
+			expected: "up to date",
+		},
+		{
+			name: "single commit and file",
+			reviewer: models.ReviewerCandidate{
+				Login:              "alice",
+				LastReviewedCommit: "abc",
+				Changes:            &models.CompareSummary{Commits: 1, FilesChanged: 1, Additions: 3, Deletions: 1},
+			},
+			expected: "1 commit, 1 file changed (+3/-1) since last review",
+		},
+		{
+			name: "multiple commits and files",
+			reviewer: models.ReviewerCandidate{
+				Login:              "alice",
+				LastReviewedCommit: "abc",
+				Changes:            &models.CompareSummary{Commits: 3, FilesChanged: 5, Additions: 120, Deletions: 30},
+			},
+			expected: "3 commits, 5 files changed (+120/-30) since last review",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatChanges(tt.reviewer)
+			if got != tt.expected {
+				t.Errorf("FormatChanges() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   *models.UserStatus
+		expected string
+	}{
+		{name: "no status", status: nil, expected: ""},
+		{name: "busy with message", status: &models.UserStatus{Busy: true, Message: "On vacation"}, expected: "busy: On vacation"},
+		{name: "busy without message", status: &models.UserStatus{Busy: true}, expected: "busy"},
+		{name: "message only", status: &models.UserStatus{Message: "Focusing"}, expected: "Focusing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatStatus(tt.status); got != tt.expected {
+				t.Errorf("FormatStatus() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatAbsence(t *testing.T) {
+	tests := []struct {
+		name     string
+		absence  *models.Absence
+		expected string
+	}{
+		{name: "no absence", absence: nil, expected: ""},
+		{
+			name:     "all-day with reason",
+			absence:  &models.Absence{To: time.Date(2024, 5, 13, 0, 0, 0, 0, time.Local), Reason: "Vacation"},
+			expected: "away until Mon May 13 (Vacation)",
+		},
+		{
+			name:     "timed",
+			absence:  &models.Absence{To: time.Date(2024, 5, 8, 17, 30, 0, 0, time.Local)},
+			expected: "away until Wed May 8 17:30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatAbsence(tt.absence); got != tt.expected {
+				t.Errorf("FormatAbsence() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		since    time.Time
+		expected string
+	}{
+		{since: time.Time{}, expected: "?"},
+		{since: now.Add(-30 * time.Second), expected: "now"},
+		{since: now.Add(-45 * time.Minute), expected: "45m"},
+		{since: now.Add(-5 * time.Hour), expected: "5h"},
+		{since: now.Add(-75 * time.Hour), expected: "3d"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAge(tt.since, now); got != tt.expected {
+			t.Errorf("FormatAge(%v) = %q, want %q", tt.since, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		timestamp string
+		expected  string
+	}{
+		{timestamp: "2024-05-06T11:59:30Z", expected: "just now"},
+		{timestamp: "2024-05-06T09:00:00Z", expected: "3h ago"},
+		{timestamp: "2024-04-06T12:00:00Z", expected: "30d ago"},
+		{timestamp: "yesterday", expected: "yesterday"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatRelativeTime(tt.timestamp, now); got != tt.expected {
+			t.Errorf("FormatRelativeTime(%q) = %q, want %q", tt.timestamp, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatPRPreview(t *testing.T) {
+	pr := models.PullRequestInfo{HeadBranch: "fix/login"}
+	preview := models.PRPreview{
+		BaseBranch:     "main",
+		ReviewRequests: []string{"alice", "@acme/backend"},
+		LatestReviews: []models.Review{
+			{User: models.User{Login: "bob"}, State: "APPROVED"},
+			{User: models.User{Login: "carol"}, State: "CHANGES_REQUESTED"},
+		},
+		Checks: &models.CheckSummary{Passed: 3, Failed: 1},
+		Body:   "Fixes the redirect loop.\n\nSteps:\n1. Log in\n2. Log out",
+	}
+
+	expected := "Branches:  fix/login → main\n" +
+		"Requested: alice, @acme/backend\n" +
+		"Reviews:   bob approved, carol changes requested\n" +
+		"Checks:    3 passed, 1 failed\n" +
+		"\n" +
+		"Fixes the redirect loop.\n" +
+		"Steps:\n" +
+		"…"
+	if got := FormatPRPreview(pr, preview, 80); got != expected {
+		t.Errorf("FormatPRPreview() =\n%s\nwant\n%s", got, expected)
+	}
+
+	empty := FormatPRPreview(models.PullRequestInfo{}, models.PRPreview{}, 20)
+	if empty != "Branches:  - → -\nRequested: -\nReviews:   -\nChecks:    no checks" {
+		t.Errorf("Unexpected preview of a PR without details:\n%s", empty)
+	}
+	if got := FormatPRPreview(pr, preview, 20); !strings.Contains(got, "Requested: alice, @…") {
+		t.Errorf("Expected lines to be truncated to the width, got:\n%s", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		width    int
+		expected string
+	}{
+		{name: "fits", input: "hello", width: 5, expected: "hello"},
+		{name: "ascii", input: "hello world", width: 8, expected: "hello w…"},
+		{name: "cjk on a boundary", input: "日本語のタイトル", width: 8, expected: "日本語…"},
+		{name: "cjk", input: "日本語のタイトル", width: 9, expected: "日本語の…"},
+		{name: "emoji", input: "🚀 launch", width: 4, expected: "🚀 …"},
+		{name: "zwj sequence kept whole", input: "👨‍👩‍👧 family", width: 3, expected: "👨‍👩‍👧…"},
+		{name: "zero width", input: "hello", width: 0, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.input, tt.width); got != tt.expected {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		input    string
+		width    int
+		expected string
+	}{
+		{input: "abc", width: 5, expected: "abc  "},
+		{input: "日本語のタイトル", width: 8, expected: "日本語… "},
+		{input: "日本語", width: 6, expected: "日本語"},
+	}
+
+	for _, tt := range tests {
+		if got := Fit(tt.input, tt.width); got != tt.expected {
+			t.Errorf("Fit(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.expected)
+		}
+	}
+}
@@ -1,30 +1,66 @@
 package ui
 
-import "github.com/ryo246912/gh-reassign-reviewer/internal/models"
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
 
 // Prompter defines interface for user interaction
 type Prompter interface {
-	SelectPR(prs []models.PullRequestInfo) (int, error)
-	SelectReviewer(reviewers []string) (string, error)
-	ConfirmSelection(reviewer string) (bool, error)
+	SelectPR(ctx context.Context, prs []models.PullRequestInfo) (models.PullRequestInfo, error)
+	SelectReviewer(ctx context.Context, reviewers []models.ReviewerCandidate) (string, error)
+	ConfirmSelection(ctx context.Context, plan models.ReassignPlan) (bool, error)
 }
 
 // DefaultPrompter implements the actual prompting logic
-type DefaultPrompter struct{}
+type DefaultPrompter struct {
+	ConfirmDefault bool          // answer used when the user just presses enter
+	ConfirmTimeout time.Duration // zero waits forever
+	Sort           SortMode      // order of the PR picker
+	Preview        PreviewLoader // describes the highlighted PR; nil shows no preview
+	Actions        *Actions      // quick actions offered after picking; nil picks directly
+}
 
 // SelectPR prompts user to select a PR
-func (p *DefaultPrompter) SelectPR(prs []models.PullRequestInfo) (int, error) {
-	return SelectPR(prs)
+func (p *DefaultPrompter) SelectPR(ctx context.Context, prs []models.PullRequestInfo) (models.PullRequestInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return models.PullRequestInfo{}, err
+	}
+	return SelectPR(prs, p.Sort, p.Preview, p.Actions)
 }
 
 // SelectReviewer prompts user to select a reviewer
-func (p *DefaultPrompter) SelectReviewer(reviewers []string) (string, error) {
-	return SelectReviewer(reviewers)
+func (p *DefaultPrompter) SelectReviewer(ctx context.Context, reviewers []models.ReviewerCandidate) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return SelectReviewer(reviewers, p.Actions)
 }
 
 // ConfirmSelection prompts user to confirm selection
-func (p *DefaultPrompter) ConfirmSelection(reviewer string) (bool, error) {
-	return ConfirmSelection(reviewer)
+func (p *DefaultPrompter) ConfirmSelection(ctx context.Context, plan models.ReassignPlan) (bool, error) {
+	return ConfirmSelection(ctx, plan, p.ConfirmDefault, p.ConfirmTimeout)
+}
+
+// NonInteractivePrompter never prompts; it is used where no user can answer, such as CI
+type NonInteractivePrompter struct{}
+
+// SelectPR fails because the PR must be given up front
+func (p *NonInteractivePrompter) SelectPR(ctx context.Context, prs []models.PullRequestInfo) (models.PullRequestInfo, error) {
+	return models.PullRequestInfo{}, fmt.Errorf("cannot select a PR in non-interactive mode; pass the PR number")
+}
+
+// SelectReviewer fails because reviewers must be given up front or chosen by a strategy
+func (p *NonInteractivePrompter) SelectReviewer(ctx context.Context, reviewers []models.ReviewerCandidate) (string, error) {
+	return "", fmt.Errorf("cannot select a reviewer in non-interactive mode; pass --reviewer or --strategy")
+}
+
+// ConfirmSelection confirms automatically since running non-interactively is an explicit opt-in
+func (p *NonInteractivePrompter) ConfirmSelection(ctx context.Context, plan models.ReassignPlan) (bool, error) {
+	return true, nil
 }
 
 // MockPrompter for testing
@@ -42,22 +78,29 @@ type MockPrompter struct {
 	SelectPRCalled         bool
 	SelectReviewerCalled   bool
 	ConfirmSelectionCalled bool
+	LastPlan               models.ReassignPlan
 }
 
-// SelectPR mocks PR selection
-func (m *MockPrompter) SelectPR(prs []models.PullRequestInfo) (int, error) {
+// SelectPR mocks PR selection, returning the listed PR numbered SelectedPRNumber
+func (m *MockPrompter) SelectPR(ctx context.Context, prs []models.PullRequestInfo) (models.PullRequestInfo, error) {
 	m.SelectPRCalled = true
-	return m.SelectedPRNumber, m.PRSelectionError
+	for _, pr := range prs {
+		if pr.Number == m.SelectedPRNumber {
+			return pr, m.PRSelectionError
+		}
+	}
+	return models.PullRequestInfo{Number: m.SelectedPRNumber}, m.PRSelectionError
 }
 
 // SelectReviewer mocks reviewer selection
-func (m *MockPrompter) SelectReviewer(reviewers []string) (string, error) {
+func (m *MockPrompter) SelectReviewer(ctx context.Context, reviewers []models.ReviewerCandidate) (string, error) {
 	m.SelectReviewerCalled = true
 	return m.SelectedReviewer, m.ReviewerSelectionError
 }
 
 // ConfirmSelection mocks confirmation
-func (m *MockPrompter) ConfirmSelection(reviewer string) (bool, error) {
+func (m *MockPrompter) ConfirmSelection(ctx context.Context, plan models.ReassignPlan) (bool, error) {
 	m.ConfirmSelectionCalled = true
+	m.LastPlan = plan
 	return m.ConfirmedSelection, m.ConfirmationError
 }
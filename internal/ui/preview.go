@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"sync"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// PreviewLoader fetches the preview of a PR
+type PreviewLoader func(pr models.PullRequestInfo) (*models.PRPreview, error)
+
+// CachePreviews wraps load so that each PR is fetched once while moving through a picker.
+// Failures are not cached, so the next highlight tries again.
+func CachePreviews(load PreviewLoader) PreviewLoader {
+	var mu sync.Mutex
+	cache := make(map[models.PRRef]*models.PRPreview)
+	return func(pr models.PullRequestInfo) (*models.PRPreview, error) {
+		ref := models.PRRef{Owner: pr.Owner, Repo: pr.Repo, Number: pr.Number}
+		mu.Lock()
+		preview, ok := cache[ref]
+		mu.Unlock()
+		if ok {
+			return preview, nil
+		}
+
+		preview, err := load(pr)
+		if err != nil {
+			return nil, err
+		}
+		mu.Lock()
+		cache[ref] = preview
+		mu.Unlock()
+		return preview, nil
+	}
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+func TestCachePreviews(t *testing.T) {
+	calls := 0
+	fail := true
+	load := CachePreviews(func(pr models.PullRequestInfo) (*models.PRPreview, error) {
+		calls++
+		if fail {
+			return nil, errors.New("timeout")
+		}
+		return &models.PRPreview{BaseBranch: pr.Repo}, nil
+	})
+	pr := models.PullRequestInfo{Owner: "acme", Repo: "api", Number: 1}
+
+	if _, err := load(pr); err == nil {
+		t.Fatal("Expected the first load to fail")
+	}
+	fail = false
+	for i := 0; i < 2; i++ {
+		preview, err := load(pr)
+		if err != nil || preview.BaseBranch != "api" {
+			t.Fatalf("Unexpected preview: %+v, %v", preview, err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Expected a failed load to be retried and a successful one cached, got %d calls", calls)
+	}
+
+	// PRs with the same number in another repository are fetched separately
+	if _, err := load(models.PullRequestInfo{Owner: "acme", Repo: "web", Number: 1}); err != nil || calls != 3 {
+		t.Errorf("Expected another repository to be fetched, got %d calls, %v", calls, err)
+	}
+}
@@ -1,101 +1,400 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/manifoldco/promptui"
+	"github.com/mattn/go-runewidth"
 	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
 )
 
-func SelectPR(prs []models.PullRequestInfo) (int, error) {
+// SelectPR prompts for a PR listed in the given order; a repository column is shown when the PRs span
+// several repositories. The search accepts fuzzy terms and qualifiers such as "author:alice draft:false label:bug".
+// With preview, the highlighted PR is described under the list. With actions, the picked PR can also be
+// opened in the browser, have its URL copied or its diff stats shown before moving on.
+func SelectPR(prs []models.PullRequestInfo, mode SortMode, preview PreviewLoader, actions *Actions) (models.PullRequestInfo, error) {
 	if len(prs) == 0 {
-		return 0, fmt.Errorf("no assigned pull requests found")
+		return models.PullRequestInfo{}, fmt.Errorf("no assigned pull requests found")
 	}
 
-	items := make([]string, len(prs))
+	if mode == "" {
+		mode = SortCreated
+	}
+	prs = SortPRs(prs, mode)
+	width := terminalWidth()
+	rows := FormatPRItems(prs, time.Now(), width)
+	items := make([]prItem, len(prs))
 	for i, pr := range prs {
-		state := pr.State
-		if pr.Draft {
-			state += " (Draft)"
-		}
-		title := pr.Title
-		if len(title) > 75 {
-			title = title[:72] + "..."
-		}
-		items[i] = fmt.Sprintf(
-			"#%s %s %s %s %s",
-			PadRight(fmt.Sprintf("%-6d", pr.Number), 7),
-			PadRight(title, 75),
-			PadRight(pr.User, 15),
-			PadRight(state, 10),
-			PadRight(pr.UpdatedAt, 20),
-		)
+		items[i] = prItem{Row: rows[i], pr: pr, load: preview, width: min(width, previewWidth)}
 	}
 
+	templates := &promptui.SelectTemplates{
+		Active:   fmt.Sprintf("%s {{ .Row | underline }}", promptui.IconSelect),
+		Inactive: "  {{ .Row }}",
+		Selected: fmt.Sprintf(`{{ "%s" | green }} {{ .Row | faint }}`, promptui.IconGood),
+	}
+	if preview != nil {
+		templates.Details = "\n{{ .Preview }}"
+	}
 	prompt := promptui.Select{
-		Label: "Select PR",
-		Items: items,
-		Size:  12,
+		Label:     fmt.Sprintf("Select PR (by %s)", mode),
+		Items:     items,
+		Size:      pickerSize,
+		Templates: templates,
 		Searcher: func(input string, index int) bool {
-			return strings.Contains(strings.ToLower(items[index]), input)
+			return ParsePRFilter(input).Match(prs[index])
 		},
 		StartInSearchMode: true,
 	}
 
-	idx, _, err := prompt.Run()
+	idx := 0
+	for {
+		var err error
+		idx, _, err = prompt.RunCursorAt(idx, idx-pickerSize+1)
+		if err != nil {
+			return models.PullRequestInfo{}, fmt.Errorf("prompt failed: %w", err)
+		}
+		if actions == nil {
+			return prs[idx], nil
+		}
+
+		pr := prs[idx]
+		done, err := runActionMenu(fmt.Sprintf("#%d", pr.Number), []action{
+			{label: "Choose reviewers", done: true},
+			{label: "Open in browser", run: func() (string, error) { return "", actions.OpenPR(pr) }},
+			{label: "Copy URL", run: func() (string, error) { return "Copied " + pr.URL, actions.CopyURL(pr) }},
+			{label: "Show diff stats", run: func() (string, error) { return FormatDiffStats(pr), nil }},
+			{label: "Back to the PR list"},
+		})
+		if err != nil {
+			return models.PullRequestInfo{}, err
+		}
+		if done {
+			return pr, nil
+		}
+	}
+}
+
+// pickerSize is how many rows the pickers show at once
+const pickerSize = 12
+
+// action is an entry of the menu shown after picking an item. Picking an action that runs stays in the
+// menu; otherwise the menu closes and done tells whether to go on with the picked item or pick again.
+type action struct {
+	label string
+	run   func() (string, error)
+	done  bool
+}
+
+// runActionMenu shows the actions available for item until one of them closes the menu
+func runActionMenu(item string, actions []action) (bool, error) {
+	labels := make([]string, len(actions))
+	for i, a := range actions {
+		labels[i] = a.label
+	}
+	prompt := promptui.Select{Label: item, Items: labels, Size: len(labels)}
+
+	for {
+		idx, _, err := prompt.Run()
+		if err != nil {
+			return false, fmt.Errorf("prompt failed: %w", err)
+		}
+		a := actions[idx]
+		if a.run == nil {
+			return a.done, nil
+		}
+		message, err := a.run()
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "%s failed: %v\n", a.label, err)
+		case message != "":
+			fmt.Println(message)
+		}
+	}
+}
+
+// prItem is a PR picker row with the preview shown under the list while it is highlighted
+type prItem struct {
+	Row   string
+	pr    models.PullRequestInfo
+	load  PreviewLoader
+	width int
+}
+
+// Preview fetches the preview when the row is first highlighted; the loader caches it from then on
+func (item prItem) Preview() string {
+	preview, err := item.load(item.pr)
 	if err != nil {
-		return 0, fmt.Errorf("prompt failed: %w", err)
+		return Truncate(fmt.Sprintf("Preview unavailable: %v", err), item.width)
+	}
+	return FormatPRPreview(item.pr, *preview, item.width)
+}
+
+// previewWidth is the widest line of the PR preview
+const previewWidth = 100
+
+// Column widths of the PR picker, in display columns
+const (
+	numberWidth   = 8 // "#" and the number
+	userWidth     = 15
+	reviewWidth   = 17
+	checksWidth   = 11
+	updatedWidth  = 8
+	maxRepoWidth  = 30
+	minTitleWidth = 20
+	maxTitleWidth = 72
+	// rowIndent is taken by the picker's cursor in front of each row
+	rowIndent = 2
+)
+
+// FormatPRItems renders one picker row per PR, at most width display columns wide, coloring the review and
+// check status. When the title would get less than its minimum width, the checks, updated and author columns
+// are dropped in that order. The title takes 3/5 of the columns left by the other columns and the details get
+// the rest, dropping what does not fit.
+func FormatPRItems(prs []models.PullRequestInfo, now time.Time, width int) []string {
+	repoWidth := 0
+	if SpansRepositories(prs) {
+		for _, pr := range prs {
+			repoWidth = max(repoWidth, runewidth.StringWidth(pr.Owner+"/"+pr.Repo))
+		}
+		repoWidth = min(repoWidth, maxRepoWidth)
+	}
+
+	showChecks, showUpdated, showUser := true, true, true
+	fixed := rowIndent + numberWidth + userWidth + reviewWidth + checksWidth + updatedWidth + 6 // one space after each column but the last
+	if repoWidth > 0 {
+		fixed += repoWidth + 1
+	}
+	for _, column := range []struct {
+		shown *bool
+		width int
+	}{{&showChecks, checksWidth}, {&showUpdated, updatedWidth}, {&showUser, userWidth}} {
+		if width-fixed >= minTitleWidth {
+			break
+		}
+		*column.shown = false
+		fixed -= column.width + 1
+	}
+	available := width - fixed
+	titleWidth := max(min(max(available*3/5, minTitleWidth), maxTitleWidth, available), 1)
+	detailsWidth := available - titleWidth
+
+	type column struct {
+		text  string
+		style func(interface{}) string
+	}
+	plain := func(v interface{}) string { return fmt.Sprint(v) }
+
+	items := make([]string, len(prs))
+	for i, pr := range prs {
+		columns := []column{{Fit(fmt.Sprintf("#%d", pr.Number), numberWidth), plain}, {Fit(pr.Title, titleWidth), plain}}
+		if showUser {
+			columns = append(columns, column{Fit(pr.User, userWidth), plain})
+		}
+		columns = append(columns, column{Fit(FormatReviewDecision(pr), reviewWidth), reviewStyle(pr)})
+		if showChecks {
+			columns = append(columns, column{Fit(FormatChecks(pr.ChecksState), checksWidth), checksStyle(pr.ChecksState)})
+		}
+		if showUpdated {
+			columns = append(columns, column{Fit(FormatRelativeTime(pr.UpdatedAt, now), updatedWidth), plain})
+		}
+
+		// The padding of the last column is trimmed before styling, where it can still be seen
+		details := formatPRDetails(pr, detailsWidth)
+		for details == "" && len(columns) > 1 {
+			last := &columns[len(columns)-1]
+			if last.text = strings.TrimRight(last.text, " "); last.text != "" {
+				break
+			}
+			columns = columns[:len(columns)-1]
+		}
+		rendered := make([]string, len(columns))
+		for j, c := range columns {
+			rendered[j] = c.style(c.text)
+		}
+		items[i] = strings.Join(rendered, " ")
+		if details != "" {
+			items[i] += " " + details
+		}
+		if repoWidth > 0 {
+			items[i] = Fit(pr.Owner+"/"+pr.Repo, repoWidth) + " " + items[i]
+		}
+	}
+	return items
+}
+
+// formatPRDetails lists the pending review requests, conflicts, labels and head branch of a PR in at most
+// width display columns; the first detail that does not fit is truncated and the following ones dropped
+func formatPRDetails(pr models.PullRequestInfo, width int) string {
+	type detail struct {
+		text  string
+		style func(interface{}) string
+	}
+	plain := func(v interface{}) string { return fmt.Sprint(v) }
+
+	var candidates []detail
+	if pr.RequestedReviewers > 0 {
+		candidates = append(candidates, detail{fmt.Sprintf("%d requested", pr.RequestedReviewers), plain})
+	}
+	if pr.Mergeable == "CONFLICTING" {
+		candidates = append(candidates, detail{"conflicts", promptui.Styler(promptui.FGRed)})
+	}
+	if len(pr.Labels) > 0 {
+		candidates = append(candidates, detail{"[" + strings.Join(pr.Labels, ", ") + "]", plain})
 	}
-	return prs[idx].Number, nil
+	if pr.HeadBranch != "" {
+		candidates = append(candidates, detail{pr.HeadBranch, promptui.Styler(promptui.FGFaint)})
+	}
+
+	var details []string
+	remaining := width
+	for _, d := range candidates {
+		if len(details) > 0 {
+			remaining-- // separating space
+		}
+		text := d.text
+		if w := runewidth.StringWidth(text); w > remaining {
+			// A stub of a couple of characters says nothing, so drop the detail instead
+			if remaining < 4 {
+				break
+			}
+			text = Truncate(text, remaining)
+			remaining = 0
+		} else {
+			remaining -= w
+		}
+		details = append(details, d.style(text))
+		if remaining <= 0 {
+			break
+		}
+	}
+	return strings.Join(details, " ")
 }
 
-// SelectReviewer shows reviewer selection prompt
-func SelectReviewer(reviewers []string) (string, error) {
+// terminalWidth is the width of the terminal on stdout, or 120 columns when it is unknown
+func terminalWidth() int {
+	width, _, err := term.FromEnv().Size()
+	if err != nil || width <= 0 {
+		return 120
+	}
+	return width
+}
+
+func reviewStyle(pr models.PullRequestInfo) func(interface{}) string {
+	switch {
+	case pr.Draft:
+		return promptui.Styler(promptui.FGFaint)
+	case pr.ReviewDecision == "APPROVED":
+		return promptui.Styler(promptui.FGGreen)
+	case pr.ReviewDecision == "CHANGES_REQUESTED":
+		return promptui.Styler(promptui.FGRed)
+	case pr.ReviewDecision == "REVIEW_REQUIRED":
+		return promptui.Styler(promptui.FGYellow)
+	default:
+		return func(v interface{}) string { return fmt.Sprint(v) }
+	}
+}
+
+func checksStyle(state string) func(interface{}) string {
+	switch state {
+	case "SUCCESS":
+		return promptui.Styler(promptui.FGGreen)
+	case "FAILURE", "ERROR":
+		return promptui.Styler(promptui.FGRed)
+	case "PENDING", "EXPECTED":
+		return promptui.Styler(promptui.FGYellow)
+	default:
+		return promptui.Styler(promptui.FGFaint)
+	}
+}
+
+// SpansRepositories reports whether the PRs belong to more than one repository
+func SpansRepositories(prs []models.PullRequestInfo) bool {
+	for i := 1; i < len(prs); i++ {
+		pr := prs[i]
+		if pr.Owner != prs[0].Owner || pr.Repo != prs[0].Repo {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectReviewer shows reviewer selection prompt; with actions, the picked reviewer's profile can be
+// opened before requesting them
+func SelectReviewer(reviewers []models.ReviewerCandidate, actions *Actions) (string, error) {
 	if len(reviewers) == 0 {
 		return "", fmt.Errorf("no available reviewers")
 	}
 
 	fmt.Println("Available reviewers:")
 	for i, reviewer := range reviewers {
-		fmt.Printf("%d. %s\n", i+1, reviewer)
+		fmt.Printf("%d. %s\n", i+1, reviewer.Login)
+	}
+
+	items := make([]string, len(reviewers))
+	for i, reviewer := range reviewers {
+		items[i] = fmt.Sprintf(
+			"%s %s",
+			PadRight(reviewer.Login, 20),
+			FormatChanges(reviewer),
+		)
+		if absence := FormatAbsence(reviewer.Absence); absence != "" {
+			items[i] += " [" + absence + "]"
+		}
+		if status := FormatStatus(reviewer.Status); status != "" {
+			items[i] += " [" + status + "]"
+		}
 	}
 
 	prompt := promptui.Select{
 		Label: "Select reviewer",
-		Items: reviewers,
-		Size:  12,
+		Items: items,
+		Size:  pickerSize,
 		Searcher: func(input string, index int) bool {
-			return strings.Contains(strings.ToLower(reviewers[index]), input)
+			return strings.Contains(strings.ToLower(reviewers[index].Login), input)
 		},
 		StartInSearchMode: true,
 	}
 
-	_, selectedReviewer, err := prompt.Run()
-	if err != nil {
-		return "", fmt.Errorf("reviewer selection failed: %w", err)
-	}
-
-	return selectedReviewer, nil
-}
-
-// ConfirmSelection asks for user confirmation
-// Similar to Python's input() with validation
-func ConfirmSelection(reviewer string) (bool, error) {
-	var confirm string
+	idx := 0
 	for {
-		fmt.Printf("You selected: %s. Is this correct? (y/n): ", reviewer)
-		if _, err := fmt.Scan(&confirm); err != nil {
-			return false, fmt.Errorf("failed to read confirmation: %w", err)
+		var err error
+		idx, _, err = prompt.RunCursorAt(idx, idx-pickerSize+1)
+		if err != nil {
+			return "", fmt.Errorf("reviewer selection failed: %w", err)
 		}
-		confirmLower := strings.ToLower(confirm)
-		switch confirmLower {
-		case "yes", "y":
-			return true, nil
-		case "no", "n":
-			return false, nil
-		default:
-			fmt.Println("Please enter 'y' or 'n'.")
+		if actions == nil {
+			return reviewers[idx].Login, nil
 		}
+
+		login := reviewers[idx].Login
+		done, err := runActionMenu(login, []action{
+			{label: "Re-request review", done: true},
+			{label: "Open profile", run: func() (string, error) { return "", actions.OpenProfile(login) }},
+			{label: "Back to the reviewers"},
+		})
+		if err != nil {
+			return "", err
+		}
+		if done {
+			return login, nil
+		}
+	}
+}
+
+// ConfirmSelection asks for user confirmation on stdin
+func ConfirmSelection(ctx context.Context, plan models.ReassignPlan, defaultAnswer bool, timeout time.Duration) (bool, error) {
+	confirmer := &Confirmer{
+		In:      os.Stdin,
+		Out:     os.Stdout,
+		IsTTY:   term.IsTerminal(os.Stdin),
+		Default: defaultAnswer,
+		Timeout: timeout,
 	}
+	return confirmer.Confirm(ctx, plan)
 }
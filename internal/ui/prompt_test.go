@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+func TestFormatPRItems(t *testing.T) {
+	single := FormatPRItems([]models.PullRequestInfo{
+		{Owner: "acme", Repo: "api", Number: 1, Title: "First"},
+		{Owner: "acme", Repo: "api", Number: 2, Title: "Second"},
+	}, time.Now(), 120)
+	if !strings.HasPrefix(single[0], "#1 ") {
+		t.Errorf("Expected no repository column within one repository, got %q", single[0])
+	}
+
+	multi := FormatPRItems([]models.PullRequestInfo{
+		{Owner: "acme", Repo: "api", Number: 1, Title: "First"},
+		{Owner: "acme", Repo: "website", Number: 2, Title: "Second"},
+	}, time.Now(), 120)
+	if !strings.HasPrefix(multi[0], "acme/api     #1 ") || !strings.HasPrefix(multi[1], "acme/website #2 ") {
+		t.Errorf("Expected an aligned repository column, got %q and %q", multi[0], multi[1])
+	}
+
+	if items := FormatPRItems(nil, time.Now(), 120); len(items) != 0 {
+		t.Errorf("Expected no items, got %v", items)
+	}
+}
+
+func TestFormatPRItems_Status(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	items := FormatPRItems([]models.PullRequestInfo{
+		{
+			Number: 1, Title: "Blocked", User: "alice", UpdatedAt: "2024-05-03T12:00:00Z",
+			ReviewDecision: "CHANGES_REQUESTED", ChecksState: "FAILURE", Mergeable: "CONFLICTING",
+			RequestedReviewers: 2, Labels: []string{"bug", "ui"}, HeadBranch: "fix/layout",
+		},
+		{Number: 2, Title: "Work in progress", User: "bob", Draft: true, UpdatedAt: "2024-05-06T11:15:00Z"},
+	}, now, 200)
+
+	for _, want := range []string{"changes requested", "✗ failing", "3d ago", "2 requested", "conflicts", "[bug, ui]", "fix/layout"} {
+		if !strings.Contains(items[0], want) {
+			t.Errorf("Expected %q in %q", want, items[0])
+		}
+	}
+	for _, want := range []string{"draft", "- no checks", "45m ago"} {
+		if !strings.Contains(items[1], want) {
+			t.Errorf("Expected %q in %q", want, items[1])
+		}
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// ansi matches the color escape sequences of the picker rows
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestFormatPRItems_Golden(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	prs := []models.PullRequestInfo{
+		{
+			Owner: "acme", Repo: "api", Number: 101, Title: "Fix login redirect loop when the session cookie has expired",
+			User: "alice", UpdatedAt: "2024-05-06T09:00:00Z", ReviewDecision: "REVIEW_REQUIRED", ChecksState: "SUCCESS",
+			RequestedReviewers: 2, Labels: []string{"bug"}, HeadBranch: "fix/login-redirect",
+		},
+		{
+			Owner: "acme", Repo: "api", Number: 102, Title: "ログイン画面のレイアウト崩れを修正し、エラーメッセージを日本語化する",
+			User: "山田太郎", UpdatedAt: "2024-05-03T12:00:00Z", ReviewDecision: "CHANGES_REQUESTED", ChecksState: "FAILURE",
+			Mergeable: "CONFLICTING", Labels: []string{"バグ", "ui"}, HeadBranch: "fix/ログイン",
+		},
+		{
+			Owner: "acme", Repo: "web", Number: 7, Title: "🚀 Ship the 新しい dashboard 🎉 with 👨‍👩‍👧 family emoji and 👍🏽 skin tones",
+			User: "bob", Draft: true, UpdatedAt: "2024-04-06T12:00:00Z", ChecksState: "PENDING", HeadBranch: "feat/🚀",
+		},
+		{
+			Owner: "acme", Repo: "web", Number: 8, Title: "修正",
+			User: "carol", UpdatedAt: "2024-05-06T11:59:30Z", ReviewDecision: "APPROVED",
+		},
+	}
+
+	for _, width := range []int{60, 80, 100, 120, 160} {
+		t.Run(fmt.Sprint(width), func(t *testing.T) {
+			items := FormatPRItems(prs, now, width)
+			var b strings.Builder
+			for _, item := range items {
+				line := ansi.ReplaceAllString(item, "")
+				if w := runewidth.StringWidth(line); w > width-rowIndent {
+					t.Errorf("Expected at most %d columns, got %d: %q", width-rowIndent, w, line)
+				}
+				if strings.HasSuffix(line, " ") {
+					t.Errorf("Expected no trailing spaces: %q", line)
+				}
+				b.WriteString(line + "\n")
+			}
+
+			path := filepath.Join("testdata", fmt.Sprintf("pr_items_%d.golden", width))
+			if *update {
+				if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+					t.Fatalf("Failed to write %s: %v", path, err)
+				}
+			}
+			golden, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read %s (run go test -update): %v", path, err)
+			}
+			if b.String() != string(golden) {
+				t.Errorf("Rows differ from %s:\n%s\nwant:\n%s", path, b.String(), golden)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+)
+
+// Deduper remembers delivery IDs so redelivered webhooks are processed only once
+type Deduper struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	now  func() time.Time
+	seen map[string]time.Time
+}
+
+// NewDeduper creates a Deduper that forgets deliveries after ttl
+func NewDeduper(ttl time.Duration) *Deduper {
+	return &Deduper{
+		ttl:  ttl,
+		now:  time.Now,
+		seen: make(map[string]time.Time),
+	}
+}
+
+// Seen records the delivery and reports whether it was already recorded.
+// A delivery whose processing fails must be forgotten so that its redelivery is not dropped.
+func (d *Deduper) Seen(delivery string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	for id, at := range d.seen {
+		if now.Sub(at) > d.ttl {
+			delete(d.seen, id)
+		}
+	}
+
+	if _, ok := d.seen[delivery]; ok {
+		return true
+	}
+	d.seen[delivery] = now
+	return false
+}
+
+// Forget removes the delivery so a redelivery is processed again
+func (d *Deduper) Forget(delivery string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.seen, delivery)
+}
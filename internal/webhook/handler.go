@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ci"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/ui"
+)
+
+// NewReassignHandler processes jobs through a ReassignService for the repository of each payload,
+// configured with opts
+func NewReassignHandler(client github.GitHubClient, logger *log.Logger, opts ...service.Option) Handler {
+	return func(ctx context.Context, job Job) error {
+		repo, err := ci.ParseRepository(job.Payload.Repository.FullName)
+		if err != nil {
+			// Retrying cannot fix a malformed payload
+			logger.Printf("delivery %s: %v", job.Delivery, err)
+			return nil
+		}
+
+		reassignService := service.NewReassignService(client, repo, &ui.NonInteractivePrompter{}, opts...)
+
+		var plan *models.ReassignPlan
+		switch job.Event {
+		case "pull_request":
+			plan, err = reassignService.HandlePullRequestEvent(ctx, job.Payload)
+		case "pull_request_review":
+			plan, err = reassignService.HandlePullRequestReviewEvent(ctx, job.Payload)
+		default:
+			err = fmt.Errorf("%w: %s", service.ErrIgnoredEvent, job.Event)
+		}
+
+		switch {
+		case errors.Is(err, service.ErrIgnoredEvent), errors.Is(err, service.ErrNothingToRequest):
+			logger.Printf("delivery %s: %v", job.Delivery, err)
+			return nil
+		case errors.Is(err, service.ErrAuditFailed):
+			// The reviewers were requested; retrying would only request them again
+			logger.Printf("delivery %s: %v", job.Delivery, err)
+			return nil
+		case err != nil:
+			return err
+		}
+
+		for _, warning := range plan.Warnings {
+			logger.Printf("delivery %s: %s", job.Delivery, warning)
+		}
+		logger.Printf("delivery %s: re-requested %v on %s/%s#%d", job.Delivery, plan.Reviewers, plan.Owner, plan.Repo, plan.PRNumber)
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// ErrQueueFull is returned when no more jobs can be accepted
+var ErrQueueFull = errors.New("job queue is full")
+
+// Job is a webhook delivery waiting to be processed
+type Job struct {
+	Delivery string
+	Event    string // X-GitHub-Event header
+	Payload  *models.PullRequestEvent
+	// Failed is called when the job is given up or cancelled, e.g. to let GitHub's redelivery through
+	Failed func()
+}
+
+// Handler processes a job; returning an error schedules a retry
+type Handler func(ctx context.Context, job Job) error
+
+// QueueOptions configures the concurrency and retries of a Queue
+type QueueOptions struct {
+	Workers     int
+	Size        int
+	MaxAttempts int
+	Backoff     time.Duration // delay before the first retry, doubled on each attempt
+	Timeout     time.Duration // limit for each attempt; zero means no limit
+}
+
+// Queue runs jobs with bounded concurrency and retries failed ones
+type Queue struct {
+	opts    QueueOptions
+	handler Handler
+	jobs    chan Job
+	wg      sync.WaitGroup
+	sleep   func(ctx context.Context, d time.Duration) error
+	logger  *log.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewQueue starts opts.Workers workers running handler
+func NewQueue(opts QueueOptions, handler Handler, logger *log.Logger) *Queue {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &Queue{
+		opts:    opts,
+		handler: handler,
+		jobs:    make(chan Job, opts.Size),
+		sleep:   sleepContext,
+		logger:  logger,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for i := 0; i < opts.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Enqueue adds a job without blocking
+func (q *Queue) Enqueue(job Job) error {
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting jobs and waits for the queued ones to finish.
+// When ctx is done first, the remaining jobs are cancelled.
+func (q *Queue) Close(ctx context.Context) error {
+	close(q.jobs)
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		q.run(job)
+	}
+}
+
+func (q *Queue) run(job Job) {
+	backoff := q.opts.Backoff
+	for attempt := 1; ; attempt++ {
+		err := q.attempt(job)
+		if err == nil {
+			return
+		}
+		if q.ctx.Err() != nil {
+			q.logger.Printf("delivery %s: cancelled: %v", job.Delivery, err)
+			job.fail()
+			return
+		}
+		if attempt >= q.opts.MaxAttempts {
+			q.logger.Printf("delivery %s: giving up after %d attempts: %v", job.Delivery, attempt, err)
+			job.fail()
+			return
+		}
+
+		// Rate limited jobs resume once the budget is available again
+		wait := backoff
+		var limited interface{ RetryAfter() time.Duration }
+		if errors.As(err, &limited) && limited.RetryAfter() > wait {
+			wait = limited.RetryAfter()
+		}
+
+		q.logger.Printf("delivery %s: attempt %d failed, retrying in %s: %v", job.Delivery, attempt, wait.Round(time.Second), err)
+		if err := q.sleep(q.ctx, wait); err != nil {
+			q.logger.Printf("delivery %s: cancelled before retrying", job.Delivery)
+			job.fail()
+			return
+		}
+		backoff *= 2
+	}
+}
+
+func (j Job) fail() {
+	if j.Failed != nil {
+		j.Failed()
+	}
+}
+
+// attempt runs the handler once within the per-attempt timeout
+func (q *Queue) attempt(job Job) error {
+	ctx := q.ctx
+	if q.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.opts.Timeout)
+		defer cancel()
+	}
+	return q.handler(ctx, job)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
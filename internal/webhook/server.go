@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+)
+
+// maxPayloadSize is the largest payload GitHub sends
+const maxPayloadSize = 25 << 20
+
+// Server receives GitHub webhooks and queues the ones that may need a re-request
+type Server struct {
+	secret  []byte
+	deduper *Deduper
+	queue   *Queue
+	logger  *log.Logger
+}
+
+// NewServer creates a webhook receiver verifying payloads with secret
+func NewServer(secret []byte, deduper *Deduper, queue *Queue, logger *log.Logger) *Server {
+	return &Server{
+		secret:  secret,
+		deduper: deduper,
+		queue:   queue,
+		logger:  logger,
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read payload", http.StatusBadRequest)
+		return
+	}
+
+	if err := VerifySignature(s.secret, body, r.Header.Get("X-Hub-Signature-256")); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	delivery := r.Header.Get("X-GitHub-Delivery")
+
+	if event != "pull_request" && event != "pull_request_review" {
+		writeStatus(w, http.StatusOK, "ignored")
+		return
+	}
+
+	var payload models.PullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if !isHandled(event, payload.Action) {
+		writeStatus(w, http.StatusOK, "ignored")
+		return
+	}
+
+	if delivery != "" && s.deduper.Seen(delivery) {
+		writeStatus(w, http.StatusOK, "duplicate")
+		return
+	}
+
+	// A failed job forgets the delivery so that GitHub's redelivery is processed
+	err = s.queue.Enqueue(Job{Delivery: delivery, Event: event, Payload: &payload, Failed: func() {
+		s.deduper.Forget(delivery)
+	}})
+	if errors.Is(err, ErrQueueFull) {
+		// Let GitHub's redelivery pick it up again
+		s.deduper.Forget(delivery)
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	s.logger.Printf("delivery %s: queued %s.%s for %s#%d", delivery, event, payload.Action, payload.Repository.FullName, payload.PullRequestNumber())
+	writeStatus(w, http.StatusAccepted, "queued")
+}
+
+// isHandled reports whether the event may lead to a re-request
+func isHandled(event, action string) bool {
+	return (event == "pull_request" && action == "synchronize") ||
+		(event == "pull_request_review" && action == "dismissed")
+}
+
+func writeStatus(w http.ResponseWriter, code int, status string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
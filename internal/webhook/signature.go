@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidSignature is returned when X-Hub-Signature-256 does not match the payload
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// VerifySignature checks the X-Hub-Signature-256 header against the HMAC of body
+func VerifySignature(secret, body []byte, header string) error {
+	hexDigest, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+
+	signature, err := hex.DecodeString(hexDigest)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Sign returns the X-Hub-Signature-256 header value for body
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
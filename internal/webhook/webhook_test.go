@@ -0,0 +1,365 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ryo246912/gh-reassign-reviewer/internal/audit"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/github"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/models"
+	"github.com/ryo246912/gh-reassign-reviewer/internal/service"
+)
+
+var testLogger = log.New(io.Discard, "", 0)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"action":"synchronize"}`)
+
+	tests := []struct {
+		name        string
+		header      string
+		expectError bool
+	}{
+		{name: "valid signature", header: Sign(secret, body)},
+		{name: "missing header", header: "", expectError: true},
+		{name: "sha1 signature", header: "sha1=abc", expectError: true},
+		{name: "not hex", header: "sha256=zz", expectError: true},
+		{name: "wrong secret", header: Sign([]byte("other"), body), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(secret, body, tt.header)
+			if tt.expectError != (err != nil) {
+				t.Errorf("VerifySignature() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestDeduper(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewDeduper(time.Hour)
+	d.now = func() time.Time { return now }
+
+	if d.Seen("a") {
+		t.Errorf("First delivery should not be seen")
+	}
+	if !d.Seen("a") {
+		t.Errorf("Redelivery should be seen")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if d.Seen("a") {
+		t.Errorf("Delivery should be forgotten after the TTL")
+	}
+
+	d.Forget("a")
+	if d.Seen("a") {
+		t.Errorf("Forgotten delivery should not be seen")
+	}
+}
+
+func TestServer(t *testing.T) {
+	secret := []byte("secret")
+	synchronize := []byte(`{"action":"synchronize","pull_request":{"number":1},"repository":{"full_name":"org/repo"}}`)
+	opened := []byte(`{"action":"opened","pull_request":{"number":1},"repository":{"full_name":"org/repo"}}`)
+
+	tests := []struct {
+		name         string
+		event        string
+		delivery     string
+		body         []byte
+		signature    string
+		expectedCode int
+	}{
+		{name: "queued", event: "pull_request", delivery: "1", body: synchronize, expectedCode: http.StatusAccepted},
+		{name: "duplicate delivery", event: "pull_request", delivery: "1", body: synchronize, expectedCode: http.StatusOK},
+		{name: "invalid signature", event: "pull_request", delivery: "2", body: synchronize, signature: "sha256=00", expectedCode: http.StatusUnauthorized},
+		{name: "unhandled event", event: "push", delivery: "3", body: []byte(`{}`), expectedCode: http.StatusOK},
+		{name: "unhandled action", event: "pull_request", delivery: "4", body: opened, expectedCode: http.StatusOK},
+		{name: "invalid payload", event: "pull_request", delivery: "5", body: []byte(`{`), expectedCode: http.StatusBadRequest},
+	}
+
+	var mu sync.Mutex
+	var handled []Job
+	queue := NewQueue(QueueOptions{Workers: 1, Size: 10}, func(ctx context.Context, job Job) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, job)
+		return nil
+	}, testLogger)
+	server := NewServer(secret, NewDeduper(time.Hour), queue, testLogger)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature := tt.signature
+			if signature == "" {
+				signature = Sign(secret, tt.body)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			req.Header.Set("X-GitHub-Event", tt.event)
+			req.Header.Set("X-GitHub-Delivery", tt.delivery)
+			req.Header.Set("X-Hub-Signature-256", signature)
+			rec := httptest.NewRecorder()
+
+			server.ServeHTTP(rec, req)
+			if rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	_ = queue.Close(context.Background())
+	if len(handled) != 1 || handled[0].Delivery != "1" || handled[0].Payload.PullRequestNumber() != 1 {
+		t.Errorf("Expected only delivery 1 to be handled, got %+v", handled)
+	}
+}
+
+func TestServer_QueueFull(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"action":"synchronize","pull_request":{"number":1},"repository":{"full_name":"org/repo"}}`)
+
+	block := make(chan struct{})
+	queue := NewQueue(QueueOptions{Workers: 1, Size: 0}, func(ctx context.Context, job Job) error {
+		<-block
+		return nil
+	}, testLogger)
+	deduper := NewDeduper(time.Hour)
+	server := NewServer(secret, deduper, queue, testLogger)
+
+	send := func(delivery string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "pull_request")
+		req.Header.Set("X-GitHub-Delivery", delivery)
+		req.Header.Set("X-Hub-Signature-256", Sign(secret, body))
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// The unbuffered queue only accepts a job once the worker is waiting for one
+	deadline := time.Now().Add(time.Second)
+	for send("busy") != http.StatusAccepted {
+		deduper.Forget("busy")
+		if time.Now().After(deadline) {
+			t.Fatalf("Worker never picked up a job")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if code := send("rejected"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while the worker is busy, got %d", code)
+	}
+	if deduper.Seen("rejected") {
+		t.Errorf("Rejected delivery should be forgotten so a redelivery is processed")
+	}
+
+	close(block)
+	_ = queue.Close(context.Background())
+}
+
+func TestQueue_Retries(t *testing.T) {
+	tests := []struct {
+		name             string
+		failures         int
+		maxAttempts      int
+		expectedAttempts int
+		expectedSleeps   []time.Duration
+		expectedFailed   bool
+	}{
+		{name: "succeeds first time", failures: 0, maxAttempts: 3, expectedAttempts: 1},
+		{name: "succeeds after retries", failures: 2, maxAttempts: 3, expectedAttempts: 3, expectedSleeps: []time.Duration{time.Second, 2 * time.Second}},
+		{name: "gives up", failures: 5, maxAttempts: 3, expectedAttempts: 3, expectedSleeps: []time.Duration{time.Second, 2 * time.Second}, expectedFailed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			queue := &Queue{
+				opts: QueueOptions{MaxAttempts: tt.maxAttempts, Backoff: time.Second},
+				handler: func(ctx context.Context, job Job) error {
+					attempts++
+					if attempts <= tt.failures {
+						return errors.New("temporary failure")
+					}
+					return nil
+				},
+				logger: testLogger,
+				ctx:    context.Background(),
+			}
+			var sleeps []time.Duration
+			queue.sleep = func(ctx context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			failed := false
+			queue.run(Job{Delivery: "1", Failed: func() { failed = true }})
+
+			if failed != tt.expectedFailed {
+				t.Errorf("Expected Failed to be called: %v, got %v", tt.expectedFailed, failed)
+			}
+			if attempts != tt.expectedAttempts {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedAttempts, attempts)
+			}
+			if len(sleeps) != len(tt.expectedSleeps) {
+				t.Fatalf("Expected sleeps %v, got %v", tt.expectedSleeps, sleeps)
+			}
+			for i := range sleeps {
+				if sleeps[i] != tt.expectedSleeps[i] {
+					t.Errorf("Expected sleeps %v, got %v", tt.expectedSleeps, sleeps)
+				}
+			}
+		})
+	}
+}
+
+func TestNewReassignHandler(t *testing.T) {
+	dismissed := &models.PullRequestEvent{Action: "dismissed"}
+	dismissed.PullRequest.Number = 7
+	dismissed.PullRequest.State = "open"
+	dismissed.PullRequest.User.Login = "author"
+	dismissed.Review.User.Login = "alice"
+	dismissed.Repository.FullName = "org/repo"
+
+	client := &github.MockClient{}
+	handler := NewReassignHandler(client, testLogger)
+
+	if err := handler(context.Background(), Job{Delivery: "1", Event: "pull_request_review", Payload: dismissed}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.LastOwner != "org" || client.LastRepo != "repo" || client.LastPRNumber != 7 {
+		t.Errorf("Unexpected request target %s/%s#%d", client.LastOwner, client.LastRepo, client.LastPRNumber)
+	}
+	if len(client.LastReviewers) != 1 || client.LastReviewers[0] != "alice" {
+		t.Errorf("Expected alice to be re-requested, got %v", client.LastReviewers)
+	}
+
+	// API failures are returned so the job is retried
+	client.Reset()
+	client.ReassignError = github.NewNetworkError()
+	if err := handler(context.Background(), Job{Delivery: "2", Event: "pull_request_review", Payload: dismissed}); err == nil {
+		t.Errorf("Expected error to trigger a retry")
+	}
+
+	// Ignored events are not retried
+	client.Reset()
+	submitted := *dismissed
+	submitted.Action = "submitted"
+	if err := handler(context.Background(), Job{Delivery: "3", Event: "pull_request_review", Payload: &submitted}); err != nil {
+		t.Errorf("Expected ignored event not to be retried, got %v", err)
+	}
+	if client.ReassignReviewersCalled {
+		t.Errorf("Expected no review request for an ignored event")
+	}
+
+	// Dismissed reviews on drafts are ignored like other events on drafts
+	client.Reset()
+	draft := *dismissed
+	draft.PullRequest.Draft = true
+	if err := handler(context.Background(), Job{Delivery: "4", Event: "pull_request_review", Payload: &draft}); err != nil {
+		t.Errorf("Expected a draft not to be retried, got %v", err)
+	}
+	if client.ReassignReviewersCalled {
+		t.Errorf("Expected no review request on a draft")
+	}
+
+	// An audit failure after the request succeeded is logged, not retried
+	client.Reset()
+	client.ReassignError = nil
+	audited := NewReassignHandler(client, testLogger, service.WithAuditLog(failingRecorder{}))
+	if err := audited(context.Background(), Job{Delivery: "5", Event: "pull_request_review", Payload: dismissed}); err != nil {
+		t.Errorf("Expected an audit failure not to be retried, got %v", err)
+	}
+	if !client.ReassignReviewersCalled {
+		t.Errorf("Expected the review to be requested before the audit failure")
+	}
+}
+
+type failingRecorder struct{}
+
+func (failingRecorder) Record(audit.Entry) error {
+	return errors.New("disk full")
+}
+
+func TestQueue_CloseCancelsRemainingJobs(t *testing.T) {
+	started := make(chan struct{})
+	queue := NewQueue(QueueOptions{Workers: 1, Size: 1, MaxAttempts: 3, Backoff: time.Hour}, func(ctx context.Context, job Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}, testLogger)
+
+	if err := queue.Enqueue(Job{Delivery: "1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := queue.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the in-flight job to be cancelled after the deadline, got %v", err)
+	}
+}
+
+func TestServer_RedeliveryAfterFailure(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"action":"synchronize","pull_request":{"number":1},"repository":{"full_name":"org/repo"}}`)
+
+	done := make(chan struct{}, 2)
+	attempts := 0
+	queue := NewQueue(QueueOptions{Workers: 1, Size: 10, MaxAttempts: 1}, func(ctx context.Context, job Job) error {
+		defer func() { done <- struct{}{} }()
+		attempts++
+		if attempts == 1 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	}, testLogger)
+	deduper := NewDeduper(time.Hour)
+	server := NewServer(secret, deduper, queue, testLogger)
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "pull_request")
+		req.Header.Set("X-GitHub-Delivery", "1")
+		req.Header.Set("X-Hub-Signature-256", Sign(secret, body))
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := send(); code != http.StatusAccepted {
+		t.Fatalf("Expected the delivery to be queued, got %d", code)
+	}
+	<-done
+	// The failure is logged after the handler returns, so wait for the delivery to be forgotten
+	deadline := time.Now().Add(time.Second)
+	for {
+		code := send()
+		if code == http.StatusAccepted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the redelivery of a failed job to be queued, got %d", code)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	<-done
+
+	if code := send(); code != http.StatusOK {
+		t.Errorf("Expected a successful delivery to stay deduplicated, got %d", code)
+	}
+	_ = queue.Close(context.Background())
+}
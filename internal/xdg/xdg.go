@@ -0,0 +1,36 @@
+// Package xdg locates the per-user directories of the extension following the XDG base directory spec
+package xdg
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// AppName is the directory created under each base directory
+const AppName = "gh-reassign-reviewer"
+
+// CacheDir returns $XDG_CACHE_HOME/gh-reassign-reviewer, defaulting to ~/.cache
+func CacheDir() string {
+	return appDir("XDG_CACHE_HOME", ".cache")
+}
+
+// ConfigDir returns $XDG_CONFIG_HOME/gh-reassign-reviewer, defaulting to ~/.config
+func ConfigDir() string {
+	return appDir("XDG_CONFIG_HOME", ".config")
+}
+
+// StateDir returns $XDG_STATE_HOME/gh-reassign-reviewer, defaulting to ~/.local/state
+func StateDir() string {
+	return appDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+func appDir(env, fallback string) string {
+	if dir := os.Getenv(env); dir != "" {
+		return filepath.Join(dir, AppName)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), AppName)
+	}
+	return filepath.Join(home, fallback, AppName)
+}